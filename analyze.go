@@ -0,0 +1,191 @@
+package editorconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrDeadSection is a section matching none of the files.
+	ErrDeadSection = errors.New("section matches no file")
+	// ErrShadowedProperty is a property overridden by a later section for
+	// all the files of its section.
+	ErrShadowedProperty = errors.New("property always overridden")
+	// ErrDuplicateSection is a section repeating the selector of a previous
+	// one.
+	ErrDuplicateSection = errors.New("duplicate section")
+)
+
+// Analyze reports the sections matching none of the files, the properties
+// overridden by a later section for all the files their section matches,
+// the last matching section having precedence, and the sections repeating
+// the selector of a previous one.
+//
+// The files are slash-separated paths relative to the directory of the
+// configuration file, see Config.Files. The problems are warnings, joined
+// into the returned error. Use ParseErrors to list them.
+func (e *Editorconfig) Analyze(files []string) error {
+	var problems []*ParseError
+
+	// matches are the files of each section, looked up for each file and
+	// each key of the previous sections.
+	matches := make([]map[string]bool, len(e.Definitions))
+	selectors := make(map[string]int, len(e.Definitions))
+
+	for i, def := range e.Definitions {
+		if line, ok := selectors[def.Selector]; ok {
+			problems = append(problems, def.sectionWarning(
+				fmt.Errorf("[%s] already on line %d: %w", def.Selector, line, ErrDuplicateSection)))
+		} else {
+			selectors[def.Selector] = def.line
+		}
+
+		s, err := CompileSelector(def.Selector)
+		if err != nil {
+			problems = append(problems, def.sectionWarning(err))
+
+			continue
+		}
+
+		matches[i] = make(map[string]bool)
+
+		for _, file := range files {
+			if s.Match(file) {
+				matches[i][file] = true
+			}
+		}
+
+		if len(matches[i]) == 0 {
+			problems = append(problems, def.sectionWarning(fmt.Errorf("[%s]: %w", def.Selector, ErrDeadSection)))
+		}
+	}
+
+	for i := range e.Definitions {
+		problems = append(problems, e.shadowed(i, matches)...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	var result error
+
+	for _, pe := range problems {
+		result = errors.Join(result, pe)
+	}
+
+	return result
+}
+
+// shadowed reports the properties of the section i overridden, for each of
+// its files, by a later section.
+func (e *Editorconfig) shadowed(i int, matches []map[string]bool) []*ParseError {
+	def := e.Definitions[i]
+	if len(matches[i]) == 0 {
+		return nil
+	}
+
+	var problems []*ParseError
+
+	for _, key := range def.keys() {
+		// overriders are the sections giving the value of key to the files.
+		overriders := make(map[int]bool)
+
+		for file := range matches[i] {
+			j := e.overrider(i, key, file, matches)
+			if j < 0 {
+				overriders = nil
+
+				break
+			}
+
+			overriders[j] = true
+		}
+
+		if overriders == nil {
+			continue
+		}
+
+		problems = append(problems, &ParseError{
+			Filename: def.filename,
+			Line:     def.lines[key],
+			Column:   def.keyColumns[key],
+			Selector: def.Selector,
+			Key:      key,
+			Value:    def.Raw[key],
+			Severity: SeverityWarning,
+			Err:      fmt.Errorf("%s is overridden by %s for all the files: %w", key, e.describe(overriders), ErrShadowedProperty),
+		})
+	}
+
+	return problems
+}
+
+// overrider returns the last section after i setting key for the file, -1
+// when none does.
+func (e *Editorconfig) overrider(i int, key string, file string, matches []map[string]bool) int {
+	for j := len(e.Definitions) - 1; j > i; j-- {
+		if _, ok := e.Definitions[j].Raw[key]; ok && matches[j][file] {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// describe names the sections, e.g. "[*.go] on line 12" or "the sections on
+// lines 12, 20".
+func (e *Editorconfig) describe(sections map[int]bool) string {
+	indexes := make([]int, 0, len(sections))
+	for i := range sections {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	if len(indexes) == 1 {
+		def := e.Definitions[indexes[0]]
+
+		return fmt.Sprintf("[%s] on line %d", def.Selector, def.line)
+	}
+
+	lines := make([]string, 0, len(indexes))
+	for _, i := range indexes {
+		lines = append(lines, strconv.Itoa(e.Definitions[i].line))
+	}
+
+	return "the sections on lines " + strings.Join(lines, ", ")
+}
+
+// sectionWarning builds the warning of a problem of the whole section.
+func (d *Definition) sectionWarning(err error) *ParseError {
+	return &ParseError{
+		Filename: d.filename,
+		Line:     d.line,
+		Column:   1,
+		Selector: d.Selector,
+		Severity: SeverityWarning,
+		Err:      err,
+	}
+}
+
+// keys returns the keys of the section, in the order of the file.
+func (d *Definition) keys() []string {
+	keys := make([]string, 0, len(d.Raw))
+	for key := range d.Raw {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if d.lines[keys[i]] != d.lines[keys[j]] {
+			return d.lines[keys[i]] < d.lines[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
@@ -0,0 +1,79 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"root = true",
+		"[*]",
+		"indent_style = space",
+		"end_of_line = lf",
+		"[*.go]",
+		"indent_style = tab",
+		"[*.rb]",
+		"indent_size = 2",
+		"[src/*]",
+		"indent_size = 4",
+		"[*.go]",
+		"indent_size = 8",
+		"[*.{js,go}]",
+		"indent_style = tab",
+	}, "\n")
+
+	ec, err := Parse(strings.NewReader(data))
+	assert.Nil(t, err)
+
+	// indent_size of [src/*] is kept by src/lib.js.
+	problems := ParseErrors(ec.Analyze([]string{"main.go", "src/lib.go", "src/lib.js", "README.md"}))
+
+	expected := []struct {
+		line   int
+		column int
+		err    error
+		msg    string
+	}{
+		{6, 1, ErrShadowedProperty, "indent_style is overridden by [*.{js,go}] on line 13 for all the files"},
+		{7, 1, ErrDeadSection, "[*.rb]"},
+		{11, 1, ErrDuplicateSection, "[*.go] already on line 5"},
+	}
+
+	assert.Equal(t, len(expected), len(problems))
+
+	for i, e := range expected {
+		assert.Equal(t, e.line, problems[i].Line)
+		assert.Equal(t, e.column, problems[i].Column)
+		assert.Equal(t, true, errors.Is(problems[i], e.err))
+		assert.Equal(t, e.msg+": "+e.err.Error(), problems[i].Err.Error())
+	}
+}
+
+func TestAnalyzeShadowedBySeveralSections(t *testing.T) {
+	t.Parallel()
+
+	ec, err := Parse(strings.NewReader("[*]\ncharset = utf-8\n[*.go]\ncharset = latin1\n[*.md]\ncharset = latin1\n"))
+	assert.Nil(t, err)
+
+	problems := ParseErrors(ec.Analyze([]string{"main.go", "README.md"}))
+	assert.Equal(t, 1, len(problems))
+	assert.Equal(t, "charset is overridden by the sections on lines 3, 5 for all the files: property always overridden", problems[0].Err.Error())
+
+	assert.Nil(t, ec.Analyze([]string{"main.go", "README.md", "Makefile"}))
+}
+
+func TestConfigFiles(t *testing.T) {
+	t.Parallel()
+
+	files, err := (&Config{FS: mapFS(matchFiles)}).Files(".")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		".editorconfig", "main.go", "nested/.editorconfig", "nested/src/not_anchor.go", "src/lib.go", "src/sub/lib.go",
+	}, files)
+}
@@ -1,29 +1,102 @@
 package editorconfig
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"regexp"
+	"sync"
+	"time"
+)
+
+// Revalidation tells how a CachedParser checks that its entries are still
+// fresh before using them.
+type Revalidation int
 
-	"gopkg.in/ini.v1"
+const (
+	// RevalidateNever keeps the entries until Invalidate or Purge are called.
+	RevalidateNever Revalidation = iota
+	// RevalidateModTime compares the modification time and the size of the
+	// file with the cached ones.
+	RevalidateModTime
+	// RevalidateContent compares the hash of the content of the file with the
+	// cached one.
+	RevalidateContent
 )
 
 // CachedParser implements the Parser interface but caches the definition and
 // the regular expressions.
+//
+// It is safe for concurrent use, a file being parsed only once even when
+// many goroutines ask for it at the same time.
+//
+// The missing files are cached as well, and follow the same revalidation
+// rules as the existing ones.
+//
+// The definitions are cached by filename, a CachedParser should therefore
+// not be shared between different filesystems.
 type CachedParser struct {
-	editorconfigs map[string]*Editorconfig
-	regexps       map[string]*regexp.Regexp
+	revalidation  Revalidation
+	mu            sync.Mutex
+	editorconfigs map[string]*cachedEditorconfig
+	globsMu       sync.RWMutex
+	globs         map[string]*Glob
+}
+
+// cachedEditorconfig is a cache entry, ready once done is closed.
+type cachedEditorconfig struct {
+	done chan struct{}
+	ec   *Editorconfig
+	err  error
+
+	// notExist marks a missing file.
+	notExist bool
+	modTime  time.Time
+	size     int64
+	hash     [sha256.Size]byte
 }
 
-// NewCachedParser initializes the CachedParser.
+// source opens and stats a file, either from the operating system or a fs.FS.
+type source struct {
+	open func() (fs.File, error)
+	stat func() (fs.FileInfo, error)
+}
+
+// NewCachedParser initializes the CachedParser, its entries are never
+// revalidated.
 func NewCachedParser() *CachedParser {
+	return NewRevalidatingCachedParser(RevalidateNever)
+}
+
+// NewRevalidatingCachedParser initializes a CachedParser checking that its
+// entries are still fresh.
+func NewRevalidatingCachedParser(revalidation Revalidation) *CachedParser {
 	return &CachedParser{
-		editorconfigs: make(map[string]*Editorconfig),
-		regexps:       make(map[string]*regexp.Regexp),
+		revalidation:  revalidation,
+		editorconfigs: make(map[string]*cachedEditorconfig),
+		globs:         make(map[string]*Glob),
 	}
 }
 
+// Invalidate removes the given filename from the cache.
+func (parser *CachedParser) Invalidate(filename string) {
+	parser.mu.Lock()
+	defer parser.mu.Unlock()
+
+	delete(parser.editorconfigs, filename)
+}
+
+// Purge removes all the files from the cache.
+func (parser *CachedParser) Purge() {
+	parser.mu.Lock()
+	defer parser.mu.Unlock()
+
+	parser.editorconfigs = make(map[string]*cachedEditorconfig)
+}
+
 // ParseIni parses the given filename to a Definition and caches the result.
 func (parser *CachedParser) ParseIni(filename string) (*Editorconfig, error) {
 	ec, warning, err := parser.ParseIniGraceful(filename)
@@ -36,56 +109,176 @@ func (parser *CachedParser) ParseIni(filename string) (*Editorconfig, error) {
 
 // ParseIniGraceful parses the given filename to a Definition and caches the result.
 func (parser *CachedParser) ParseIniGraceful(filename string) (*Editorconfig, error, error) {
-	var warning error
+	return parser.parseIniGraceful(filename, source{
+		open: func() (fs.File, error) {
+			return os.Open(filename)
+		},
+		stat: func() (fs.FileInfo, error) {
+			return os.Stat(filename)
+		},
+	})
+}
+
+// ParseIniFS parses the given filename from fsys to a Definition and caches
+// the result.
+func (parser *CachedParser) ParseIniFS(fsys fs.FS, filename string) (*Editorconfig, error) {
+	ec, warning, err := parser.ParseIniGracefulFS(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
 
-	empty := (*Editorconfig)(nil)
+	return ec, warning
+}
 
-	ec, ok := parser.editorconfigs[filename]
-	if !ok {
-		fp, err := os.Open(filename)
-		if err != nil {
-			return empty, nil, fmt.Errorf("error opening %q: %w", filename, err)
+// ParseIniGracefulFS parses the given filename from fsys to a Definition and
+// caches the result.
+func (parser *CachedParser) ParseIniGracefulFS(fsys fs.FS, filename string) (*Editorconfig, error, error) {
+	return parser.parseIniGraceful(filename, source{
+		open: func() (fs.File, error) {
+			return fsys.Open(filename)
+		},
+		stat: func() (fs.FileInfo, error) {
+			return fs.Stat(fsys, filename)
+		},
+	})
+}
+
+// parseIniGraceful returns the cached Editorconfig or parses it. The warnings
+// are only returned to the caller which did the parsing.
+//
+// Concurrent callers wait for the ongoing parsing, the errors other than a
+// missing file are not cached.
+func (parser *CachedParser) parseIniGraceful(filename string, src source) (*Editorconfig, error, error) {
+	for {
+		parser.mu.Lock()
+
+		entry, ok := parser.editorconfigs[filename]
+		if !ok {
+			entry = &cachedEditorconfig{
+				done: make(chan struct{}),
+			}
+			parser.editorconfigs[filename] = entry
+			parser.mu.Unlock()
+
+			return parser.fill(entry, filename, src)
 		}
 
-		defer fp.Close()
+		parser.mu.Unlock()
 
-		iniFile, err := ini.Load(fp)
-		if err != nil {
-			return empty, nil, fmt.Errorf("error loading ini file %q: %w", filename, err)
+		<-entry.done
+
+		if parser.fresh(entry, src) {
+			return entry.ec, nil, entry.err
 		}
 
-		var warn error
+		parser.forget(filename, entry)
+	}
+}
 
-		ec, warn, err = newEditorconfig(iniFile)
-		if err != nil {
-			return empty, nil, fmt.Errorf("error creating config: %w", err)
+// forget removes the cache entry of the filename, unless it has already been
+// replaced by another one.
+func (parser *CachedParser) forget(filename string, entry *cachedEditorconfig) {
+	parser.mu.Lock()
+	defer parser.mu.Unlock()
+
+	if parser.editorconfigs[filename] == entry {
+		delete(parser.editorconfigs, filename)
+	}
+}
+
+// fill loads the file into the cache entry.
+func (parser *CachedParser) fill(entry *cachedEditorconfig, filename string, src source) (*Editorconfig, error, error) {
+	defer close(entry.done)
+
+	if parser.revalidation == RevalidateModTime {
+		info, err := src.stat()
+		if err == nil {
+			entry.modTime = info.ModTime()
+			entry.size = info.Size()
 		}
+	}
 
-		if warn != nil {
-			warning = errors.Join(warning, warn)
+	data, err := readAll(src)
+	if err != nil {
+		entry.err = fmt.Errorf("error opening %q: %w", filename, err)
+		entry.notExist = errors.Is(err, fs.ErrNotExist)
+
+		if !entry.notExist {
+			parser.forget(filename, entry)
 		}
 
-		parser.editorconfigs[filename] = ec
+		return nil, nil, entry.err
 	}
 
+	entry.hash = sha256.Sum256(data)
+
+	ec, warning, err := loadEditorconfig(data, filename)
+	if err != nil {
+		entry.err = fmt.Errorf("error loading ini file %q: %w", filename, err)
+
+		parser.forget(filename, entry)
+
+		return nil, nil, entry.err
+	}
+
+	entry.ec = ec
+
 	return ec, warning, nil
 }
 
+// fresh tells whether the cache entry can be used.
+func (parser *CachedParser) fresh(entry *cachedEditorconfig, src source) bool {
+	switch parser.revalidation {
+	case RevalidateModTime:
+		info, err := src.stat()
+		if err != nil {
+			return entry.notExist && errors.Is(err, fs.ErrNotExist)
+		}
+
+		return !entry.notExist && info.ModTime().Equal(entry.modTime) && info.Size() == entry.size
+	case RevalidateContent:
+		data, err := readAll(src)
+		if err != nil {
+			return entry.notExist && errors.Is(err, fs.ErrNotExist)
+		}
+
+		hash := sha256.Sum256(data)
+
+		return !entry.notExist && bytes.Equal(hash[:], entry.hash[:])
+	default:
+		return true
+	}
+}
+
+func readAll(src source) ([]byte, error) {
+	fp, err := src.open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer fp.Close()
+
+	return io.ReadAll(fp) //nolint:wrapcheck
+}
+
 // FnmatchCase calls the module's FnmatchCase and caches the parsed selector.
 func (parser *CachedParser) FnmatchCase(selector string, filename string) (bool, error) {
-	r, ok := parser.regexps[selector]
-	if !ok {
-		p := translate(selector)
+	parser.globsMu.RLock()
+	g, ok := parser.globs[selector]
+	parser.globsMu.RUnlock()
 
+	if !ok {
 		var err error
 
-		r, err = regexp.Compile(fmt.Sprintf("^%s$", p))
+		g, err = CompileGlob(selector)
 		if err != nil {
 			return false, fmt.Errorf("error compiling selector %q: %w", selector, err)
 		}
 
-		parser.regexps[selector] = r
+		parser.globsMu.Lock()
+		parser.globs[selector] = g
+		parser.globsMu.Unlock()
 	}
 
-	return r.MatchString(filename), nil
+	return g.Match(filename), nil
 }
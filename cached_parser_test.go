@@ -0,0 +1,253 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+// countingFS counts the files opened.
+type countingFS struct {
+	fs.FS
+	opened atomic.Int64
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opened.Add(1)
+
+	return c.FS.Open(name) //nolint:wrapcheck
+}
+
+func (c *countingFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(c.FS, name) //nolint:wrapcheck
+}
+
+func TestCachedParserConcurrent(t *testing.T) {
+	t.Parallel()
+
+	fsys := &countingFS{FS: mapFS(testFiles)}
+	config := &Config{
+		FS:     fsys,
+		Parser: NewCachedParser(),
+	}
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			def, err := config.Load("src/main.go")
+			assert.Nil(t, err)
+			assert.Equal(t, IndentStyleTab, def.IndentStyle)
+			assert.Equal(t, EndOfLineLf, def.EndOfLine)
+		}()
+	}
+
+	wg.Wait()
+
+	// src/.editorconfig and .editorconfig
+	assert.Equal(t, int64(2), fsys.opened.Load())
+}
+
+// blockingFS fails to open the files, once released.
+type blockingFS struct {
+	opening chan struct{}
+	release chan struct{}
+}
+
+var errBlocked = errors.New("blocked")
+
+func (b *blockingFS) Open(string) (fs.File, error) {
+	close(b.opening)
+	<-b.release
+
+	return nil, errBlocked
+}
+
+func TestCachedParserFailedFillKeepsNewEntry(t *testing.T) {
+	t.Parallel()
+
+	parser := NewCachedParser()
+	blocking := &blockingFS{
+		opening: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+
+	failed := make(chan error)
+
+	go func() {
+		_, _, err := parser.ParseIniGracefulFS(blocking, ".editorconfig")
+		failed <- err
+	}()
+
+	<-blocking.opening
+
+	// the failing entry is replaced while it is being filled.
+	parser.Invalidate(".editorconfig")
+
+	counting := &countingFS{FS: mapFS(testFiles)}
+
+	_, _, err := parser.ParseIniGracefulFS(counting, ".editorconfig")
+	assert.Nil(t, err)
+
+	close(blocking.release)
+	assert.Equal(t, true, errors.Is(<-failed, errBlocked))
+
+	_, _, err = parser.ParseIniGracefulFS(counting, ".editorconfig")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), counting.opened.Load())
+}
+
+func TestCachedParserConcurrentOS(t *testing.T) {
+	t.Parallel()
+
+	parser := NewCachedParser()
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			def, err := (&Config{Parser: parser}).Load("testdata/root/src/dummy.go")
+			assert.Nil(t, err)
+			assert.Equal(t, "4", def.IndentSize)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestCachedParserRevalidation(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		revalidation Revalidation
+		touch        bool
+		fresh        bool
+	}{
+		{"never", RevalidateNever, true, false},
+		{"modtime", RevalidateModTime, true, true},
+		{"modtime untouched", RevalidateModTime, false, false},
+		{"content", RevalidateContent, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fsys := mapFS(testFiles)
+			parser := NewRevalidatingCachedParser(test.revalidation)
+			config := &Config{
+				FS:     fsys,
+				Parser: parser,
+			}
+
+			def, err := config.Load("src/main.go")
+			assert.Nil(t, err)
+			assert.Equal(t, "4", def.IndentSize)
+
+			// same size, to only rely on the modification time.
+			fsys["src/.editorconfig"].Data = []byte("[*.go]\nindent_style = tab\nindent_size = 8\n")
+			if test.touch {
+				fsys["src/.editorconfig"].ModTime = time.Now()
+			}
+
+			expected := "4"
+			if test.fresh {
+				expected = "8"
+			}
+
+			def, err = config.Load("src/main.go")
+			assert.Nil(t, err)
+			assert.Equal(t, expected, def.IndentSize)
+
+			parser.Invalidate("src/.editorconfig")
+
+			def, err = config.Load("src/main.go")
+			assert.Nil(t, err)
+			assert.Equal(t, "8", def.IndentSize)
+		})
+	}
+}
+
+func TestCachedParserNegative(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFS(testFiles)
+	counting := &countingFS{FS: fsys}
+	parser := NewRevalidatingCachedParser(RevalidateModTime)
+	config := &Config{
+		FS:     counting,
+		Parser: parser,
+	}
+
+	_, err := config.Load("lib/main.go")
+	assert.Nil(t, err)
+
+	_, err = config.Load("lib/main.go")
+	assert.Nil(t, err)
+
+	// lib/.editorconfig and .editorconfig, only stat'ed the second time.
+	assert.Equal(t, int64(2), counting.opened.Load())
+
+	fsys["lib/.editorconfig"] = &fstest.MapFile{
+		Data: []byte("[*.go]\nindent_style = tab\n"),
+	}
+
+	def, err := config.Load("lib/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, IndentStyleTab, def.IndentStyle)
+
+	fsys["src/.editorconfig"].Data = nil
+
+	parser.Purge()
+
+	def, err = config.Load("src/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, IndentStyleSpaces, def.IndentStyle)
+}
+
+func TestCachedParserNegativeNeverRevalidated(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFS(testFiles)
+	counting := &countingFS{FS: fsys}
+	parser := NewCachedParser()
+	config := &Config{
+		FS:     counting,
+		Parser: parser,
+	}
+
+	def, err := config.Load("lib/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, IndentStyleSpaces, def.IndentStyle)
+
+	fsys["lib/.editorconfig"] = &fstest.MapFile{
+		Data: []byte("[*.go]\nindent_style = tab\n"),
+	}
+
+	// the missing file is not looked for again.
+	def, err = config.Load("lib/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, IndentStyleSpaces, def.IndentStyle)
+	assert.Equal(t, int64(2), counting.opened.Load())
+
+	parser.Invalidate("lib/.editorconfig")
+
+	def, err = config.Load("lib/main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, IndentStyleTab, def.IndentStyle)
+}
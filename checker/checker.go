@@ -0,0 +1,326 @@
+// Package checker verifies that the content of a file follows the rules of
+// its editorconfig definition.
+package checker
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// Rules, named after the property defining them.
+const (
+	RuleCharset                = "charset"
+	RuleEndOfLine              = "end_of_line"
+	RuleIndentSize             = "indent_size"
+	RuleIndentStyle            = "indent_style"
+	RuleInsertFinalNewline     = "insert_final_newline"
+	RuleMaxLineLength          = "max_line_length"
+	RuleTrimTrailingWhitespace = "trim_trailing_whitespace"
+)
+
+// defaultTabWidth is used when neither tab_width nor indent_size are set.
+const defaultTabWidth = 8
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16BE = []byte{0xfe, 0xff}
+	bomUTF16LE = []byte{0xff, 0xfe}
+)
+
+// Violation is a rule of the definition that the content does not follow.
+//
+// Line and Column start at 1, the column counts characters, not bytes.
+type Violation struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the violation as line:column: rule: message.
+func (v Violation) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", v.Line, v.Column, v.Rule, v.Message)
+}
+
+// Check reads the content from r and returns the violations of the rules
+// given by the definition.
+func Check(def *editorconfig.Definition, r io.Reader) ([]Violation, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read content: %w", err)
+	}
+
+	c := &checker{
+		def:      def,
+		tabWidth: tabWidth(def),
+	}
+
+	return c.check(data), nil
+}
+
+type checker struct {
+	def        *editorconfig.Definition
+	tabWidth   int
+	violations []Violation
+}
+
+func (c *checker) report(line int, column int, rule string, format string, args ...any) {
+	c.violations = append(c.violations, Violation{
+		Line:    line,
+		Column:  column,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (c *checker) check(data []byte) []Violation {
+	data, ok := c.checkCharset(data)
+	if !ok {
+		return c.violations
+	}
+
+	if len(data) == 0 {
+		return c.violations
+	}
+
+	eol := eolSequence(c.def.EndOfLine)
+	maxLineLength := c.def.MaxLineLength
+
+	num := 0
+
+	for len(data) > 0 {
+		num++
+
+		var line, terminator []byte
+
+		line, terminator, data = nextLine(data)
+
+		c.checkIndentation(num, line)
+
+		if c.def.TrimTrailingWhitespace != nil && *c.def.TrimTrailingWhitespace {
+			trimmed := bytes.TrimRight(line, " \t")
+			if len(trimmed) != len(line) {
+				c.report(num, utf8.RuneCount(trimmed)+1, RuleTrimTrailingWhitespace, "trailing whitespace")
+			}
+		}
+
+		if maxLineLength > 0 {
+			if column := c.overflow(line, maxLineLength); column > 0 {
+				c.report(num, column, RuleMaxLineLength, "line is longer than %d characters", maxLineLength)
+			}
+		}
+
+		if eol != "" && len(terminator) > 0 && string(terminator) != eol {
+			c.report(
+				num, utf8.RuneCount(line)+1, RuleEndOfLine,
+				"line ends with %s, expected %s", eolName(string(terminator)), c.def.EndOfLine,
+			)
+		}
+
+		if len(data) == 0 {
+			c.checkFinalNewline(num, line, terminator)
+		}
+	}
+
+	return c.violations
+}
+
+// checkCharset verifies the byte order mark and encoding. It returns the
+// content without the byte order mark, and whether the lines can be checked.
+func (c *checker) checkCharset(data []byte) ([]byte, bool) {
+	hasBOM := bytes.HasPrefix(data, bomUTF8)
+
+	switch c.def.Charset {
+	case editorconfig.CharsetUTF8BOM:
+		if !hasBOM {
+			c.report(1, 1, RuleCharset, "missing UTF-8 byte order mark")
+		}
+
+		if !utf8.Valid(bytes.TrimPrefix(data, bomUTF8)) {
+			c.reportInvalidUTF8(bytes.TrimPrefix(data, bomUTF8))
+		}
+	case editorconfig.CharsetUTF8:
+		if hasBOM {
+			c.report(1, 1, RuleCharset, "unexpected UTF-8 byte order mark")
+		}
+
+		if !utf8.Valid(bytes.TrimPrefix(data, bomUTF8)) {
+			c.reportInvalidUTF8(bytes.TrimPrefix(data, bomUTF8))
+		}
+	case editorconfig.CharsetUTF16BE:
+		if !bytes.HasPrefix(data, bomUTF16BE) {
+			c.report(1, 1, RuleCharset, "missing UTF-16BE byte order mark")
+		}
+
+		return data, false
+	case editorconfig.CharsetUTF16LE:
+		if !bytes.HasPrefix(data, bomUTF16LE) {
+			c.report(1, 1, RuleCharset, "missing UTF-16LE byte order mark")
+		}
+
+		return data, false
+	}
+
+	return bytes.TrimPrefix(data, bomUTF8), true
+}
+
+// reportInvalidUTF8 reports the position of the first invalid sequence.
+func (c *checker) reportInvalidUTF8(data []byte) {
+	num := 1
+	column := 1
+
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			c.report(num, column, RuleCharset, "invalid UTF-8 sequence")
+
+			return
+		}
+
+		if r == '\n' {
+			num++
+			column = 1
+		} else {
+			column++
+		}
+
+		data = data[size:]
+	}
+}
+
+func (c *checker) checkIndentation(num int, line []byte) {
+	indent := line[:len(line)-len(bytes.TrimLeft(line, " \t"))]
+	if len(indent) == 0 {
+		return
+	}
+
+	switch c.def.IndentStyle {
+	case editorconfig.IndentStyleTab:
+		lastTab := bytes.LastIndexByte(indent, '\t')
+
+		if i := bytes.IndexByte(indent, ' '); i >= 0 && i < lastTab {
+			c.report(num, i+1, RuleIndentStyle, "indentation mixes spaces and tabs")
+
+			return
+		}
+
+		if spaces := len(indent) - lastTab - 1; spaces >= c.tabWidth {
+			c.report(num, lastTab+2, RuleIndentStyle, "indentation uses spaces, expected tabs")
+		}
+	case editorconfig.IndentStyleSpaces:
+		if i := bytes.IndexByte(indent, '\t'); i >= 0 {
+			c.report(num, i+1, RuleIndentStyle, "indentation uses tabs, expected spaces")
+
+			return
+		}
+
+		size, err := strconv.Atoi(c.def.IndentSize)
+		if err != nil || size <= 0 || len(indent)%size == 0 {
+			return
+		}
+
+		// Leading star of a block comment continuation, e.g. " * foo".
+		if bytes.HasPrefix(line[len(indent):], []byte("*")) && len(indent)%size == 1 {
+			return
+		}
+
+		c.report(
+			num, len(indent)+1, RuleIndentSize,
+			"indentation of %d spaces is not a multiple of %d", len(indent), size,
+		)
+	}
+}
+
+func (c *checker) checkFinalNewline(num int, line []byte, terminator []byte) {
+	if c.def.InsertFinalNewline == nil {
+		return
+	}
+
+	switch {
+	case *c.def.InsertFinalNewline && len(terminator) == 0:
+		c.report(num, utf8.RuneCount(line)+1, RuleInsertFinalNewline, "missing final newline")
+	case !*c.def.InsertFinalNewline && len(terminator) > 0:
+		c.report(num, utf8.RuneCount(line)+1, RuleInsertFinalNewline, "unexpected final newline")
+	}
+}
+
+// overflow returns the column of the first character beyond the maximum
+// length, or 0 when the line is short enough. Tabs are expanded to the next
+// tab stop.
+func (c *checker) overflow(line []byte, maxLength int) int {
+	width := 0
+	column := 0
+
+	for _, r := range string(line) {
+		column++
+
+		if r == '\t' {
+			width += c.tabWidth - width%c.tabWidth
+		} else {
+			width++
+		}
+
+		if width > maxLength {
+			return column
+		}
+	}
+
+	return 0
+}
+
+// nextLine splits the first line from data, a line being terminated by
+// either LF, CRLF or CR.
+func nextLine(data []byte) ([]byte, []byte, []byte) {
+	i := bytes.IndexAny(data, "\r\n")
+	if i < 0 {
+		return data, nil, nil
+	}
+
+	if data[i] == '\r' && i+1 < len(data) && data[i+1] == '\n' {
+		return data[:i], data[i : i+2], data[i+2:]
+	}
+
+	return data[:i], data[i : i+1], data[i+1:]
+}
+
+func eolSequence(endOfLine string) string {
+	switch endOfLine {
+	case editorconfig.EndOfLineLf:
+		return "\n"
+	case editorconfig.EndOfLineCr:
+		return "\r"
+	case editorconfig.EndOfLineCrLf:
+		return "\r\n"
+	default:
+		return ""
+	}
+}
+
+func eolName(sequence string) string {
+	switch sequence {
+	case "\n":
+		return editorconfig.EndOfLineLf
+	case "\r":
+		return editorconfig.EndOfLineCr
+	default:
+		return editorconfig.EndOfLineCrLf
+	}
+}
+
+// tabWidth returns the width of a tab, as defined by tab_width or indent_size.
+func tabWidth(def *editorconfig.Definition) int {
+	if def.TabWidth > 0 {
+		return def.TabWidth
+	}
+
+	if size, err := strconv.Atoi(def.IndentSize); err == nil && size > 0 {
+		return size
+	}
+
+	return defaultTabWidth
+}
@@ -0,0 +1,128 @@
+package checker //nolint:testpackage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestCheck(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		def        editorconfig.Definition
+		content    string
+		violations []Violation
+	}{
+		{
+			name:    "empty",
+			def:     editorconfig.Definition{InsertFinalNewline: boolPtr(true)},
+			content: "",
+		},
+		{
+			name:    "end of line",
+			def:     editorconfig.Definition{EndOfLine: editorconfig.EndOfLineLf},
+			content: "a\r\nb\nc\r",
+			violations: []Violation{
+				{1, 2, RuleEndOfLine, "line ends with crlf, expected lf"},
+				{3, 2, RuleEndOfLine, "line ends with cr, expected lf"},
+			},
+		},
+		{
+			name:    "trailing whitespace",
+			def:     editorconfig.Definition{TrimTrailingWhitespace: boolPtr(true)},
+			content: "ok\nnot ok \t\r\n",
+			violations: []Violation{
+				{2, 7, RuleTrimTrailingWhitespace, "trailing whitespace"},
+			},
+		},
+		{
+			name:    "missing final newline",
+			def:     editorconfig.Definition{InsertFinalNewline: boolPtr(true)},
+			content: "a\nbc",
+			violations: []Violation{
+				{2, 3, RuleInsertFinalNewline, "missing final newline"},
+			},
+		},
+		{
+			name:    "unexpected final newline",
+			def:     editorconfig.Definition{InsertFinalNewline: boolPtr(false)},
+			content: "a\n",
+			violations: []Violation{
+				{1, 2, RuleInsertFinalNewline, "unexpected final newline"},
+			},
+		},
+		{
+			name:    "indent with tabs",
+			def:     editorconfig.Definition{IndentStyle: editorconfig.IndentStyleTab, TabWidth: 4},
+			content: "\tok\n\t  aligned\n \tmixed\n\t    spaces\n",
+			violations: []Violation{
+				{3, 1, RuleIndentStyle, "indentation mixes spaces and tabs"},
+				{4, 2, RuleIndentStyle, "indentation uses spaces, expected tabs"},
+			},
+		},
+		{
+			name:    "indent with spaces",
+			def:     editorconfig.Definition{IndentStyle: editorconfig.IndentStyleSpaces, IndentSize: "2"},
+			content: "/*\n * comment\n */\n  ok\n   odd\n\ttab\n",
+			violations: []Violation{
+				{5, 4, RuleIndentSize, "indentation of 3 spaces is not a multiple of 2"},
+				{6, 1, RuleIndentStyle, "indentation uses tabs, expected spaces"},
+			},
+		},
+		{
+			name:    "utf-8 with bom",
+			def:     editorconfig.Definition{Charset: editorconfig.CharsetUTF8},
+			content: "\xef\xbb\xbfa\n\xffb\n",
+			violations: []Violation{
+				{1, 1, RuleCharset, "unexpected UTF-8 byte order mark"},
+				{2, 1, RuleCharset, "invalid UTF-8 sequence"},
+			},
+		},
+		{
+			name:    "utf-8-bom without bom",
+			def:     editorconfig.Definition{Charset: editorconfig.CharsetUTF8BOM},
+			content: "a\n",
+			violations: []Violation{
+				{1, 1, RuleCharset, "missing UTF-8 byte order mark"},
+			},
+		},
+		{
+			name:    "utf-16le",
+			def:     editorconfig.Definition{Charset: editorconfig.CharsetUTF16LE, TrimTrailingWhitespace: boolPtr(true)},
+			content: "\xfe\xffa \x00",
+			violations: []Violation{
+				{1, 1, RuleCharset, "missing UTF-16LE byte order mark"},
+			},
+		},
+		{
+			name: "max line length",
+			def: editorconfig.Definition{
+				TabWidth:      4,
+				MaxLineLength: 6,
+			},
+			content: "123456\n1234567\n\t12\n\t123\n",
+			violations: []Violation{
+				{2, 7, RuleMaxLineLength, "line is longer than 6 characters"},
+				{4, 4, RuleMaxLineLength, "line is longer than 6 characters"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations, err := Check(&test.def, strings.NewReader(test.content))
+			assert.Nil(t, err)
+			assert.Equal(t, test.violations, violations)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/checker"
+)
+
+// runCheck verifies the content of the given files, or of the files below
+// the given directories, against their definition.
+//
+// It returns 1 when a file does not follow its definition, and 2 when a file
+// cannot be checked.
+func runCheck(args []string) int {
+	return check(os.Stdout, args)
+}
+
+// check runs the check command, writing the report to w.
+func check(w io.Writer, args []string) int {
+	var format string
+
+	c := new(checkRun)
+
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	walker := newFileWalker(flags, c.fail)
+	flags.StringVar(&format, "format", reportPlain, "Output format: plain, json, checkstyle or sarif")
+	flags.Parse(args) //nolint:errcheck
+
+	report, ok := reporters[format]
+	if !ok {
+		flags.Usage()
+
+		return 2
+	}
+
+	paths := flags.Args()
+	if len(paths) < 1 {
+		paths = []string{"."}
+	}
+
+	walker.walk(paths, c.checkFile)
+
+	if err := report(w, c.results); err != nil {
+		log.Print(err)
+
+		return 2
+	}
+
+	switch {
+	case c.failed:
+		return 2
+	case len(c.results) > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// fileViolations are the violations found in a file.
+type fileViolations struct {
+	Filename   string
+	Violations []checker.Violation
+}
+
+// checkRun holds the state of the check command.
+type checkRun struct {
+	results []fileViolations
+	failed  bool
+}
+
+func (c *checkRun) fail(err error) {
+	log.Print(err)
+
+	c.failed = true
+}
+
+// checkFile checks a file, skipping the binary ones when walking.
+func (c *checkRun) checkFile(filename string, def *editorconfig.Definition, walking bool) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		c.fail(fmt.Errorf("cannot read %q: %w", filename, err))
+
+		return
+	}
+
+	if walking && isBinary(def, data) {
+		return
+	}
+
+	violations, err := checker.Check(def, bytes.NewReader(data))
+	if err != nil {
+		c.fail(fmt.Errorf("cannot check %q: %w", filename, err))
+
+		return
+	}
+
+	if len(violations) > 0 {
+		c.results = append(c.results, fileViolations{
+			Filename:   filename,
+			Violations: violations,
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package main //nolint:testpackage
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestCheckExitCode(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTree(t, dir, map[string]string{
+		".editorconfig": "root = true\n[*]\ntrim_trailing_whitespace = true\n",
+		".gitignore":    "ignored.txt\n",
+		"clean.txt":     "clean\n",
+		"dirty.txt":     "dirty \n",
+		"ignored.txt":   "dirty \n",
+		"binary.bin":    "\x00dirty \n",
+	})
+
+	dirty := "dirty.txt:1:6: trim_trailing_whitespace: trailing whitespace\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		paths    []string
+		expected int
+		report   string
+	}{
+		{"clean", nil, []string{"clean.txt"}, 0, ""},
+		{"dirty", nil, []string{"clean.txt", "dirty.txt"}, 1, dirty},
+		{"ignored", nil, []string{"ignored.txt"}, 0, ""},
+		{"directory", nil, []string{"."}, 1, dirty},
+		{"excluded", []string{"-exclude", "dirty.txt"}, []string{"."}, 0, ""},
+		{"missing", nil, []string{"missing.txt"}, 2, ""},
+		{"missing and dirty", nil, []string{"missing.txt", "dirty.txt"}, 2, dirty},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := append([]string(nil), test.args...)
+			for _, path := range test.paths {
+				args = append(args, filepath.Join(dir, path))
+			}
+
+			buf := bytes.NewBuffer(nil)
+
+			assert.Equal(t, test.expected, check(buf, args))
+			assert.Equal(t, test.report, strings.ReplaceAll(buf.String(), dir+string(filepath.Separator), ""))
+		})
+	}
+}
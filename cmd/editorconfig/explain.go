@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// explain writes the properties of the section, followed by where their
+// values come from and the values they override.
+func explain(w io.Writer, section *ini.Section, def *editorconfig.Definition) error {
+	buf := bytes.NewBuffer(nil)
+
+	if section.Name() != ini.DefaultSection {
+		fmt.Fprintf(buf, "[%s]\n", section.Name())
+	}
+
+	for _, key := range section.Keys() {
+		fmt.Fprintf(buf, "%s=%s\n", key.Name(), key.Value())
+
+		origin := def.Origin(key.Name())
+		if origin == nil {
+			fmt.Fprintf(buf, "\t(derived from the other properties)\n")
+
+			continue
+		}
+
+		fmt.Fprintf(buf, "\tset by %s\n", formatOrigin(origin))
+
+		for _, shadowed := range origin.Shadowed {
+			fmt.Fprintf(buf, "\toverrides %s=%s from %s\n", key.Name(), shadowed.Value, formatOrigin(shadowed))
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("cannot write explanation: %w", err)
+	}
+
+	return nil
+}
+
+func formatOrigin(origin *editorconfig.Origin) string {
+	return fmt.Sprintf("%s:%d [%s]", origin.Filename, origin.Line, origin.Selector)
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/gitignore"
+)
+
+// binaryPeekSize is the number of bytes looked at to detect a binary file.
+const binaryPeekSize = 8000
+
+// fileWalker finds the files given on the command line, or below the given
+// directories, with their definition.
+type fileWalker struct {
+	config       *editorconfig.Config
+	excludes     []string
+	useGitignore bool
+	// fail reports the errors, the walk going on.
+	fail func(err error)
+}
+
+// newFileWalker registers the flags of the fileWalker on the flag set.
+func newFileWalker(flags *flag.FlagSet, fail func(err error)) *fileWalker {
+	w := &fileWalker{
+		config: &editorconfig.Config{
+			Parser: editorconfig.NewCachedParser(),
+		},
+		fail: fail,
+	}
+
+	flags.StringVar(&w.config.Name, "f", editorconfig.ConfigNameDefault, "Specify conf filename other than '.editorconfig'")
+	flags.StringVar(&w.config.Version, "b", "", "Specify version (used by devs to test compatibility)")
+	flags.Func("exclude", "Exclude the paths matching a .gitignore-like pattern (repeatable)", func(pattern string) error {
+		w.excludes = append(w.excludes, pattern)
+
+		return nil
+	})
+	flags.BoolVar(&w.useGitignore, "gitignore", true, "Skip the files ignored by the .gitignore files")
+
+	return w
+}
+
+// walk calls fn for each file, walking telling whether it was found below a
+// directory rather than given on the command line.
+func (w *fileWalker) walk(paths []string, fn func(filename string, def *editorconfig.Definition, walking bool)) {
+	for _, path := range paths {
+		w.walkPath(path, fn)
+	}
+}
+
+// walkPath walks a file or a directory. The paths are matched against the
+// exclude patterns, relative to the directory given on the command line or
+// to the one of fileBase for a file, and against the .gitignore files from
+// the root of the git repository down to the file.
+func (w *fileWalker) walkPath(path string, fn func(filename string, def *editorconfig.Definition, walking bool)) {
+	info, err := os.Stat(path)
+	if err != nil {
+		w.fail(err)
+
+		return
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		w.fail(err)
+
+		return
+	}
+
+	base, dir := abs, abs
+	if !info.IsDir() {
+		base, dir = fileBase(abs), filepath.Dir(abs)
+	}
+
+	matcher, err := w.matcher(base, dir)
+	if err != nil {
+		w.fail(err)
+
+		return
+	}
+
+	if ignored(matcher, abs, info.IsDir()) {
+		return
+	}
+
+	if !info.IsDir() {
+		def, err := w.config.Load(path)
+		if err != nil {
+			w.fail(fmt.Errorf("cannot load the definition of %q: %w", path, err))
+
+			return
+		}
+
+		fn(path, def, false)
+
+		return
+	}
+
+	err = w.config.WalkDefinitions(path, func(name string, d fs.DirEntry, def *editorconfig.Definition, err error) error {
+		if err != nil {
+			w.fail(err)
+
+			return nil
+		}
+
+		if d == nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(path, name)
+		if err != nil {
+			w.fail(err)
+
+			return skip(d)
+		}
+
+		absName := filepath.Join(abs, rel)
+
+		if absName != abs && matcher.Match(absName, d.IsDir()) {
+			return skip(d)
+		}
+
+		if !d.IsDir() {
+			fn(name, def, true)
+
+			return nil
+		}
+
+		if d.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		if w.useGitignore && absName != abs {
+			if err := matcher.AddFile(absName); err != nil {
+				w.fail(err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		w.fail(err)
+	}
+}
+
+// fileBase returns the directory the exclude patterns of a file given on the
+// command line are relative to: the current directory when the file is below
+// it, and the directory of the file otherwise.
+func fileBase(abs string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return filepath.Dir(abs)
+	}
+
+	rel, err := filepath.Rel(cwd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Dir(abs)
+	}
+
+	return cwd
+}
+
+// matcher returns the matcher of the exclude patterns, relative to the base
+// directory, and of the .gitignore files of the directory and its parents
+// within the git repository.
+func (w *fileWalker) matcher(base string, dir string) (*gitignore.Matcher, error) {
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	matcher := new(gitignore.Matcher)
+
+	for _, pattern := range w.excludes {
+		if err := matcher.Add(base, pattern); err != nil {
+			return nil, fmt.Errorf("invalid exclude: %w", err)
+		}
+	}
+
+	if !w.useGitignore {
+		return matcher, nil
+	}
+
+	for _, d := range gitAncestors(dir) {
+		if err := matcher.AddFile(d); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+
+	return matcher, nil
+}
+
+// gitAncestors returns the directories from the root of the git repository
+// containing the directory down to the directory itself, or only the
+// directory when it is not within a git repository.
+func gitAncestors(dir string) []string {
+	dirs := []string{dir}
+
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			slices.Reverse(dirs)
+
+			return dirs
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			return []string{dir}
+		}
+
+		d = parent
+		dirs = append(dirs, d)
+	}
+}
+
+// ignored tells whether the absolute path, or one of its parent directories,
+// is matched.
+func ignored(matcher *gitignore.Matcher, path string, isDir bool) bool {
+	for {
+		if matcher.Match(path, isDir) {
+			return true
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+
+		path, isDir = parent, true
+	}
+}
+
+// skip skips the directory, or the file.
+func skip(d fs.DirEntry) error {
+	if d != nil && d.IsDir() {
+		return fs.SkipDir
+	}
+
+	return nil
+}
+
+// isBinary tells whether the content looks binary, i.e. contains a NUL byte
+// while not being expected to be UTF-16.
+func isBinary(def *editorconfig.Definition, data []byte) bool {
+	if strings.HasPrefix(def.Charset, "utf-16") {
+		return false
+	}
+
+	return bytes.IndexByte(data[:min(len(data), binaryPeekSize)], 0) >= 0
+}
@@ -0,0 +1,137 @@
+package main //nolint:testpackage
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+// writeTree writes the files, by slash-separated path, below the directory.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filename), 0o755))
+		assert.Nil(t, os.WriteFile(filename, []byte(content), 0o600))
+	}
+}
+
+// walkTree walks the paths, relative to the directory, with the flags and
+// returns the slash-separated files found.
+func walkTree(t *testing.T, dir string, args []string, paths ...string) []string {
+	t.Helper()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	walker := newFileWalker(flags, func(err error) {
+		t.Error(err)
+	})
+
+	assert.Nil(t, flags.Parse(args))
+
+	for i, path := range paths {
+		paths[i] = filepath.Join(dir, filepath.FromSlash(path))
+	}
+
+	files := make([]string, 0)
+
+	walker.walk(paths, func(filename string, _ *editorconfig.Definition, _ bool) {
+		rel, err := filepath.Rel(dir, filename)
+		assert.Nil(t, err)
+
+		files = append(files, filepath.ToSlash(rel))
+	})
+
+	sort.Strings(files)
+
+	return files
+}
+
+func TestFileWalker(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writeTree(t, dir, map[string]string{
+		".editorconfig":            "root = true\n",
+		".git/config":              "",
+		".gitignore":               "*.log\nbuild/\n",
+		"main.go":                  "",
+		"debug.log":                "",
+		"build/out.txt":            "",
+		"src/lib.go":               "",
+		"src/lib.min.js":           "",
+		"src/.gitignore":           "gen/\n!keep.log\n",
+		"src/keep.log":             "",
+		"src/gen/generated.go":     "",
+		"vendor/dep/dep.go":        "",
+		"vendor/dep/.editorconfig": "root = true\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		paths    []string
+		expected []string
+	}{
+		{
+			name:  "gitignore",
+			paths: []string{"."},
+			expected: []string{
+				".editorconfig", ".gitignore", "main.go", "src/.gitignore", "src/keep.log", "src/lib.go",
+				"src/lib.min.js", "vendor/dep/.editorconfig", "vendor/dep/dep.go",
+			},
+		},
+		{
+			name:  "no gitignore",
+			args:  []string{"-gitignore=false"},
+			paths: []string{"."},
+			expected: []string{
+				".editorconfig", ".gitignore", "build/out.txt", "debug.log", "main.go", "src/.gitignore",
+				"src/gen/generated.go", "src/keep.log", "src/lib.go", "src/lib.min.js", "vendor/dep/.editorconfig",
+				"vendor/dep/dep.go",
+			},
+		},
+		{
+			name:     "excludes",
+			args:     []string{"-exclude", "vendor/", "-exclude", "*.min.js", "-exclude", ".*"},
+			paths:    []string{"."},
+			expected: []string{"main.go", "src/keep.log", "src/lib.go"},
+		},
+		{
+			name:     "parent gitignore",
+			paths:    []string{"src"},
+			expected: []string{"src/.gitignore", "src/keep.log", "src/lib.go", "src/lib.min.js"},
+		},
+		{
+			name:     "named files",
+			paths:    []string{"main.go", "debug.log", "build/out.txt", "src/gen/generated.go", "src/keep.log"},
+			expected: []string{"main.go", "src/keep.log"},
+		},
+		{
+			name:     "named files without gitignore",
+			args:     []string{"-gitignore=false", "-exclude", "*.log"},
+			paths:    []string{"main.go", "debug.log", "build/out.txt"},
+			expected: []string{"build/out.txt", "main.go"},
+		},
+		{
+			name:     "ignored directory",
+			paths:    []string{"build"},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, walkTree(t, dir, test.args, test.paths...))
+		})
+	}
+}
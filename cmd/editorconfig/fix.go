@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/checker"
+	"github.com/editorconfig/editorconfig-core-go/v2/fixer"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/diff"
+)
+
+// runFix rewrites the given files, or the files below the given directories,
+// to follow their definition.
+//
+// It returns 1 when a file would be changed by a dry run, and 2 when a file
+// cannot be fixed.
+func runFix(args []string) int {
+	f := new(fixRun)
+
+	flags := flag.NewFlagSet("fix", flag.ExitOnError)
+	walker := newFileWalker(flags, f.fail)
+	flags.BoolVar(&f.dryRun, "dry-run", false, "Print a unified diff of the changes instead of writing them")
+	flags.Parse(args) //nolint:errcheck
+
+	paths := flags.Args()
+	if len(paths) < 1 {
+		paths = []string{"."}
+	}
+
+	walker.walk(paths, f.fixFile)
+
+	switch {
+	case f.failed:
+		return 2
+	case f.dryRun && f.changed:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// fixRun holds the state of the fix command.
+type fixRun struct {
+	dryRun  bool
+	changed bool
+	failed  bool
+}
+
+func (f *fixRun) fail(err error) {
+	log.Print(err)
+
+	f.failed = true
+}
+
+// fixFile rewrites the file when its content does not follow its definition,
+// the binary files being skipped.
+func (f *fixRun) fixFile(filename string, def *editorconfig.Definition, walking bool) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		f.fail(fmt.Errorf("cannot read %q: %w", filename, err))
+
+		return
+	}
+
+	if isBinary(def, data) {
+		if !walking {
+			log.Printf("skipping binary file %s", filename)
+		}
+
+		return
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if err := fixer.Fix(def, buf, bytes.NewReader(data)); err != nil {
+		f.fail(fmt.Errorf("cannot fix %q: %w", filename, err))
+
+		return
+	}
+
+	if bytes.Equal(data, buf.Bytes()) {
+		return
+	}
+
+	f.changed = true
+
+	if f.dryRun {
+		os.Stdout.Write(diff.Unified(filename+".orig", filename, data, buf.Bytes())) //nolint:errcheck
+
+		return
+	}
+
+	if err := writeFile(filename, buf.Bytes()); err != nil {
+		f.fail(err)
+
+		return
+	}
+
+	rules := fixedRules(def, data, buf.Bytes())
+	if len(rules) > 0 {
+		fmt.Printf("fixed %s: %s\n", filename, strings.Join(rules, ", ")) //nolint:forbidigo
+	} else {
+		fmt.Printf("fixed %s\n", filename) //nolint:forbidigo
+	}
+}
+
+// fixedRules lists the rules violated by the old content and followed by the
+// new one.
+func fixedRules(def *editorconfig.Definition, oldData, newData []byte) []string {
+	oldViolations, err := checker.Check(def, bytes.NewReader(oldData))
+	if err != nil {
+		return nil
+	}
+
+	newViolations, err := checker.Check(def, bytes.NewReader(newData))
+	if err != nil {
+		return nil
+	}
+
+	remaining := make(map[string]bool, len(newViolations))
+	for _, v := range newViolations {
+		remaining[v.Rule] = true
+	}
+
+	var rules []string
+
+	for _, v := range oldViolations {
+		if !remaining[v.Rule] {
+			remaining[v.Rule] = true
+			rules = append(rules, v.Rule)
+		}
+	}
+
+	return rules
+}
+
+// writeFile replaces the file atomically, through a temporary file renamed
+// over it, keeping its permissions and special bits. A symbolic link is
+// followed so that the file it points to is replaced, not the link itself.
+func writeFile(filename string, data []byte) error {
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return fmt.Errorf("cannot resolve %q: %w", filename, err)
+	}
+
+	filename = target
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fmt.Errorf("cannot stat %q: %w", filename, err)
+	}
+
+	fp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*")
+	if err != nil {
+		return fmt.Errorf("cannot write %q: %w", filename, err)
+	}
+
+	tmp := fp.Name()
+
+	_, err = fp.Write(data)
+	if err == nil {
+		err = fp.Chmod(info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky))
+	}
+
+	if closeErr := fp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp, filename)
+	}
+
+	if err != nil {
+		os.Remove(tmp) //nolint:errcheck
+
+		return fmt.Errorf("cannot write %q: %w", filename, err)
+	}
+
+	return nil
+}
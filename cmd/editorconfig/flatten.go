@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// runFlatten prints a single root .editorconfig file equivalent to the ones
+// of the given directory. When it would not be equivalent, the files whose
+// properties would change are reported instead.
+//
+// It returns 1 when a file would change, and 2 on errors.
+func runFlatten(args []string) int {
+	var configName, configVersion string
+
+	flags := flag.NewFlagSet("flatten", flag.ExitOnError)
+	flags.StringVar(&configName, "f", editorconfig.ConfigNameDefault, "Specify conf filename other than '.editorconfig'")
+	flags.StringVar(&configVersion, "b", "", "Specify version (used by devs to test compatibility)")
+	flags.Parse(args) //nolint:errcheck
+
+	rest := flags.Args()
+	if len(rest) > 1 {
+		flags.Usage()
+
+		return 2
+	}
+
+	config := &editorconfig.Config{
+		Name:    configName,
+		Version: configVersion,
+		Parser:  editorconfig.NewCachedParser(),
+	}
+
+	f, differences, err := config.Flatten(append(rest, ".")[0])
+	if err != nil {
+		log.Print(err)
+
+		return 2
+	}
+
+	if len(differences) > 0 {
+		for _, d := range differences {
+			for _, line := range changedProperties(d.Nested, d.Flattened) {
+				log.Printf("%s: %s", d.Filename, line)
+			}
+		}
+
+		return 1
+	}
+
+	if _, err := f.WriteTo(os.Stdout); err != nil {
+		log.Print(err)
+
+		return 2
+	}
+
+	return 0
+}
+
+// changedProperties describes the properties having a different value, e.g.
+// "indent_size: 2 becomes 4".
+func changedProperties(before *editorconfig.Definition, after *editorconfig.Definition) []string {
+	keys := make(map[string]bool)
+
+	for key := range before.Raw {
+		keys[key] = true
+	}
+
+	for key := range after.Raw {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+
+	sort.Strings(sorted)
+
+	var lines []string
+
+	for _, key := range sorted {
+		b, okBefore := before.Raw[key]
+		a, okAfter := after.Raw[key]
+
+		switch {
+		case !okAfter:
+			lines = append(lines, fmt.Sprintf("%s: %s is lost", key, b))
+		case !okBefore:
+			lines = append(lines, fmt.Sprintf("%s: %s is added", key, a))
+		case a != b:
+			lines = append(lines, fmt.Sprintf("%s: %s becomes %s", key, b, a))
+		}
+	}
+
+	return lines
+}
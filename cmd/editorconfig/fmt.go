@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/diff"
+)
+
+// runFmt formats the given .editorconfig files, or the standard input, and
+// prints the result.
+//
+// It returns 1 when -d finds a file not formatted, and 2 on errors.
+func runFmt(args []string) int {
+	var write, showDiff bool
+
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	flags.BoolVar(&write, "w", false, "Write the result to the files instead of the standard output")
+	flags.BoolVar(&showDiff, "d", false, "Print a unified diff of the changes instead of the result")
+	flags.Parse(args) //nolint:errcheck
+
+	files := flags.Args()
+
+	if len(files) < 1 {
+		if write {
+			flags.Usage()
+
+			return 2
+		}
+
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Print(err)
+
+			return 2
+		}
+
+		changed, err := fmtContent("<standard input>", data, showDiff)
+		if err != nil {
+			log.Print(err)
+
+			return 2
+		}
+
+		if showDiff && changed {
+			return 1
+		}
+
+		return 0
+	}
+
+	status := 0
+
+	for _, file := range files {
+		changed, err := fmtFile(file, write, showDiff)
+		if err != nil {
+			log.Print(err)
+
+			status = 2
+
+			continue
+		}
+
+		if showDiff && changed && status == 0 {
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// fmtFile formats a file, writing it back or printing the result.
+func fmtFile(filename string, write bool, showDiff bool) (bool, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return false, fmt.Errorf("cannot read %q: %w", filename, err)
+	}
+
+	if !write {
+		return fmtContent(filename, data, showDiff)
+	}
+
+	formatted, err := editorconfig.Format(data)
+	if err != nil {
+		return false, fmt.Errorf("cannot format %q: %w", filename, err)
+	}
+
+	if bytes.Equal(data, formatted) {
+		return false, nil
+	}
+
+	if showDiff {
+		os.Stdout.Write(diff.Unified(filename+".orig", filename, data, formatted)) //nolint:errcheck
+	}
+
+	return true, writeFile(filename, formatted)
+}
+
+// fmtContent prints the formatted content, or its diff.
+func fmtContent(filename string, data []byte, showDiff bool) (bool, error) {
+	formatted, err := editorconfig.Format(data)
+	if err != nil {
+		return false, fmt.Errorf("cannot format %q: %w", filename, err)
+	}
+
+	if showDiff {
+		os.Stdout.Write(diff.Unified(filename+".orig", filename, data, formatted)) //nolint:errcheck
+	} else {
+		os.Stdout.Write(formatted) //nolint:errcheck
+	}
+
+	return !bytes.Equal(data, formatted), nil
+}
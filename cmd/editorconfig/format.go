@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// Output formats.
+const (
+	formatINI  = "ini"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+var errUnknownFormat = errors.New("unknown format")
+
+// writeDefinitions writes the definitions, by filename, in the JSON or YAML
+// format.
+func writeDefinitions(w io.Writer, format string, definitions map[string]*editorconfig.Definition) error {
+	data, err := json.MarshalIndent(definitions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal the definitions: %w", err)
+	}
+
+	switch format {
+	case formatJSON:
+		_, err = fmt.Fprintf(w, "%s\n", data)
+	case formatYAML:
+		// the YAML document is built from the JSON one, so that both share
+		// the same fields.
+		var value any
+
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("cannot decode the definitions: %w", err)
+		}
+
+		buf := bytes.NewBuffer(nil)
+		writeYAML(buf, value, 0)
+
+		_, err = buf.WriteTo(w)
+	default:
+		return fmt.Errorf("%q: %w", format, errUnknownFormat)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot write the definitions: %w", err)
+	}
+
+	return nil
+}
+
+// writeYAML writes a decoded JSON value as YAML, the keys being sorted.
+func writeYAML(buf *bytes.Buffer, value any, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		fmt.Fprintf(buf, "%s%s\n", indent, yamlScalar(value))
+
+		return
+	}
+
+	if len(m) == 0 {
+		fmt.Fprintf(buf, "%s{}\n", indent)
+
+		return
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child, ok := m[key].(map[string]any)
+		if !ok {
+			fmt.Fprintf(buf, "%s%s: %s\n", indent, yamlString(key), yamlScalar(m[key]))
+
+			continue
+		}
+
+		if len(child) == 0 {
+			fmt.Fprintf(buf, "%s%s: {}\n", indent, yamlString(key))
+
+			continue
+		}
+
+		fmt.Fprintf(buf, "%s%s:\n", indent, yamlString(key))
+		writeYAML(buf, child, depth+1)
+	}
+}
+
+// yamlScalar formats a JSON scalar.
+func yamlScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	default:
+		return yamlString(fmt.Sprint(v))
+	}
+}
+
+// yamlString returns the string as a plain scalar when it is a word, a path
+// or a name which cannot be read as a number, a date or a keyword, and
+// double-quoted otherwise.
+func yamlString(s string) string {
+	if isYAMLPlain(s) {
+		return s
+	}
+
+	// a JSON string is a valid YAML double-quoted scalar.
+	data, _ := json.Marshal(s) //nolint:errchkjson
+
+	return string(data)
+}
+
+// isYAMLPlain tells whether s starts with an ASCII letter, followed by ASCII
+// letters, digits, or _-./ characters, and is not a keyword.
+func isYAMLPlain(s string) bool {
+	if s == "" || !isASCIILetter(s[0]) || isYAMLKeyword(s) {
+		return false
+	}
+
+	for i := range len(s) {
+		c := s[i]
+		if !isASCIILetter(c) && ('0' > c || c > '9') && !strings.ContainsRune("_-./", rune(c)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// isYAMLKeyword tells whether s would be read as a boolean or null.
+func isYAMLKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "y", "n", "on", "off", "null":
+		return true
+	}
+
+	return false
+}
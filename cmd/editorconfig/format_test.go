@@ -0,0 +1,103 @@
+package main //nolint:testpackage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestYAMLString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"tab", "tab"},
+		{"utf-8", "utf-8"},
+		{"src/main.go", "src/main.go"},
+		{"indent_size", "indent_size"},
+		{"", `""`},
+		{"4", `"4"`},
+		{"0x10", `"0x10"`},
+		{"0o17", `"0o17"`},
+		{"1_000", `"1_000"`},
+		{"1e3", `"1e3"`},
+		{".inf", `".inf"`},
+		{"-.inf", `"-.inf"`},
+		{".NaN", `".NaN"`},
+		{"~", `"~"`},
+		{"2001-12-14", `"2001-12-14"`},
+		{"12:30:00", `"12:30:00"`},
+		{"off", `"off"`},
+		{"Yes", `"Yes"`},
+		{"NULL", `"NULL"`},
+		{"a: b", `"a: b"`},
+		{"*.go", `"*.go"`},
+		{"#comment", `"#comment"`},
+		{" tab", `" tab"`},
+		{"élan", `"élan"`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, yamlString(test.value))
+	}
+}
+
+func TestWriteDefinitions(t *testing.T) {
+	t.Parallel()
+
+	trim := true
+	definitions := map[string]*editorconfig.Definition{
+		"main.go": {
+			IndentStyle:            "tab",
+			TabWidth:               8,
+			TrimTrailingWhitespace: &trim,
+			MaxLineLength:          editorconfig.MaxLineLengthOff,
+			Raw: map[string]string{
+				"indent_style":             "tab",
+				"max_line_length":          "off",
+				"trim_trailing_whitespace": "true",
+			},
+		},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{formatJSON, `{
+  "main.go": {
+    "indent_style": "tab",
+    "tab_width": 8,
+    "trim_trailing_whitespace": true,
+    "raw": {
+      "indent_style": "tab",
+      "max_line_length": "off",
+      "trim_trailing_whitespace": "true"
+    },
+    "max_line_length": "off"
+  }
+}
+`},
+		{formatYAML, `main.go:
+  indent_style: tab
+  max_line_length: "off"
+  raw:
+    indent_style: tab
+    max_line_length: "off"
+    trim_trailing_whitespace: "true"
+  tab_width: 8
+  trim_trailing_whitespace: true
+`},
+	}
+
+	for _, test := range tests {
+		buf := bytes.NewBuffer(nil)
+
+		assert.Nil(t, writeDefinitions(buf, test.format, definitions))
+		assert.Equal(t, test.expected, buf.String())
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"sort"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// runLintConfig validates the given .editorconfig files, or the one of the
+// current directory. With -tree, the sections are also analyzed over the
+// files below the directory of each .editorconfig file.
+//
+// It returns 1 when a file has problems, and 2 when a file cannot be read.
+func runLintConfig(args []string) int {
+	var (
+		configName string
+		tree       bool
+	)
+
+	flags := flag.NewFlagSet("lint-config", flag.ExitOnError)
+	flags.StringVar(&configName, "f", editorconfig.ConfigNameDefault, "Specify conf filename other than '.editorconfig'")
+	flags.BoolVar(&tree, "tree", false, "Report the sections matching no file, and the properties always overridden")
+	flags.Parse(args) //nolint:errcheck
+
+	files := flags.Args()
+	if len(files) < 1 {
+		files = []string{configName}
+	}
+
+	parser := new(editorconfig.SimpleParser)
+	status := 0
+
+	var config *editorconfig.Config
+	if tree {
+		config = &editorconfig.Config{
+			Name: configName,
+		}
+	}
+
+	for _, file := range files {
+		problems, err := lintConfigFile(parser, config, file)
+		if err != nil {
+			log.Print(err)
+
+			status = 2
+
+			continue
+		}
+
+		for _, pe := range problems {
+			fmt.Printf("%s:%d:%d: %s: %s\n", file, pe.Line, pe.Column, pe.Severity, pe.Err) //nolint:forbidigo
+		}
+
+		if len(problems) > 0 && status == 0 {
+			status = 1
+		}
+	}
+
+	return status
+}
+
+// lintConfigFile returns the problems of the file, analyzing it over the
+// files of its directory when the config is given.
+func lintConfigFile(parser *editorconfig.SimpleParser, config *editorconfig.Config, filename string) ([]*editorconfig.ParseError, error) {
+	ec, warning, err := parser.ParseIniGraceful(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %w", filename, err)
+	}
+
+	problems := ec.Problems(warning)
+
+	if config != nil {
+		files, err := config.Files(filepath.Dir(filename))
+		if err != nil {
+			return nil, fmt.Errorf("cannot list the files of %q: %w", filename, err)
+		}
+
+		problems = append(problems, editorconfig.ParseErrors(ec.Analyze(files))...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems, nil
+}
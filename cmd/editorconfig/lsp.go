@@ -0,0 +1,26 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/lsp"
+)
+
+// runLSP serves the Language Server Protocol over the standard input and
+// output.
+//
+// It returns 1 when the server stops without being shut down.
+func runLSP(args []string) int {
+	flags := flag.NewFlagSet("lsp", flag.ExitOnError)
+	flags.Parse(args) //nolint:errcheck
+
+	if err := lsp.NewServer(version).Serve(os.Stdin, os.Stdout); err != nil {
+		log.Print(err)
+
+		return 1
+	}
+
+	return 0
+}
@@ -14,17 +14,38 @@ import (
 // version indicates the current version number.
 var version = "dev"
 
+// commands are the subcommands, given as the first argument.
+var commands = map[string]func(args []string) int{
+	"check":       runCheck,
+	"fix":         runFix,
+	"flatten":     runFlatten,
+	"fmt":         runFmt,
+	"lint-config": runLintConfig,
+	"lsp":         runLSP,
+	"match":       runMatch,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:]))
+		}
+	}
+
 	var (
 		configName      string
 		configVersion   string
+		format          string
 		showVersionFlag bool
+		explainFlag     bool
 	)
 
 	flag.StringVar(&configName, "f", editorconfig.ConfigNameDefault, "Specify conf filename other than '.editorconfig'")
 	flag.StringVar(&configVersion, "b", "", "Specify version (used by devs to test compatibility)")
+	flag.StringVar(&format, "format", formatINI, "Output format: ini, json or yaml")
 	flag.BoolVar(&showVersionFlag, "v", false, "Display version information")
 	flag.BoolVar(&showVersionFlag, "version", false, "Display version information")
+	flag.BoolVar(&explainFlag, "explain", false, "Display which file and section set each property (ini format only)")
 	flag.Parse()
 
 	if showVersionFlag {
@@ -34,7 +55,13 @@ func main() {
 
 	rest := flag.Args()
 
-	if len(rest) < 1 {
+	if len(rest) < 1 || format != formatINI && format != formatJSON && format != formatYAML {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if explainFlag && format != formatINI {
+		fmt.Fprintf(os.Stderr, "-explain cannot be used with -format %s\n", format)
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -49,12 +76,20 @@ func main() {
 		config.Parser = editorconfig.NewCachedParser()
 	}
 
+	definitions := make(map[string]*editorconfig.Definition, len(rest))
+
 	for _, file := range rest {
 		def, err := config.Load(file)
 		if err != nil {
 			log.Fatal(err)
 		}
 
+		if format != formatINI {
+			definitions[file] = def
+
+			continue
+		}
+
 		iniFile := ini.Empty()
 		ini.PrettyFormat = false
 
@@ -66,9 +101,20 @@ func main() {
 
 		def.InsertToIniFile(iniFile)
 
-		_, err = iniFile.WriteTo(os.Stdout)
+		if explainFlag {
+			err = explain(os.Stdout, iniFile.Section(def.Selector), def)
+		} else {
+			_, err = iniFile.WriteTo(os.Stdout)
+		}
+
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
+
+	if format != formatINI {
+		if err := writeDefinitions(os.Stdout, format, definitions); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// runMatch lists the files matched by a selector below a directory, or the
+// number of files matched by each section of its configuration file.
+//
+// It returns 1 when nothing matches, or when a section matches nothing, and
+// 2 on errors.
+func runMatch(args []string) int {
+	var (
+		configName string
+		sections   bool
+	)
+
+	flags := flag.NewFlagSet("match", flag.ExitOnError)
+	flags.StringVar(&configName, "f", editorconfig.ConfigNameDefault, "Specify conf filename other than '.editorconfig'")
+	flags.BoolVar(&sections, "sections", false, "Count the files matched by each section of the configuration file")
+	flags.Parse(args) //nolint:errcheck
+
+	rest := flags.Args()
+
+	config := &editorconfig.Config{
+		Name: configName,
+	}
+
+	if sections {
+		if len(rest) > 1 {
+			flags.Usage()
+
+			return 2
+		}
+
+		return matchSections(config, append(rest, ".")[0])
+	}
+
+	if len(rest) < 1 || len(rest) > 2 {
+		flags.Usage()
+
+		return 2
+	}
+
+	files, err := config.MatchFiles(append(rest, ".")[1], rest[0])
+	if err != nil {
+		log.Print(err)
+
+		return 2
+	}
+
+	for _, file := range files {
+		fmt.Println(file) //nolint:forbidigo
+	}
+
+	if len(files) == 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// matchSections prints the number of files matched by each section.
+func matchSections(config *editorconfig.Config, dir string) int {
+	matches, err := config.MatchSections(dir)
+	if err != nil {
+		log.Print(err)
+
+		return 2
+	}
+
+	filename := filepath.Join(dir, config.ConfigName())
+	status := 0
+
+	for _, m := range matches {
+		if len(m.Files) == 0 {
+			fmt.Printf("%s:%d: [%s] matches nothing\n", filename, m.Line, m.Selector) //nolint:forbidigo
+
+			status = 1
+
+			continue
+		}
+
+		fmt.Printf("%s:%d: [%s] %d files\n", filename, m.Line, m.Selector, len(m.Files)) //nolint:forbidigo
+	}
+
+	return status
+}
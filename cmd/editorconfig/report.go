@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+)
+
+// Report formats of the check command.
+const (
+	reportPlain      = "plain"
+	reportJSON       = "json"
+	reportCheckstyle = "checkstyle"
+	reportSARIF      = "sarif"
+)
+
+// reporters write the violations found by the check command.
+var reporters = map[string]func(w io.Writer, results []fileViolations) error{
+	reportPlain:      writePlain,
+	reportJSON:       writeJSONReport,
+	reportCheckstyle: writeCheckstyle,
+	reportSARIF:      writeSARIF,
+}
+
+// writePlain writes one violation per line, e.g. "main.go:3:1: rule: message".
+func writePlain(w io.Writer, results []fileViolations) error {
+	for _, result := range results {
+		for _, violation := range result.Violations {
+			if _, err := fmt.Fprintf(w, "%s:%s\n", result.Filename, violation); err != nil {
+				return fmt.Errorf("cannot write the report: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeJSONReport writes an array of violations, each with its filename.
+func writeJSONReport(w io.Writer, results []fileViolations) error {
+	type violation struct {
+		Filename string `json:"filename"`
+		Line     int    `json:"line"`
+		Column   int    `json:"column"`
+		Rule     string `json:"rule"`
+		Message  string `json:"message"`
+	}
+
+	violations := make([]violation, 0)
+
+	for _, result := range results {
+		for _, v := range result.Violations {
+			violations = append(violations, violation{result.Filename, v.Line, v.Column, v.Rule, v.Message})
+		}
+	}
+
+	return writeJSON(w, violations)
+}
+
+// writeCheckstyle writes the violations in the XML format of Checkstyle.
+func writeCheckstyle(w io.Writer, results []fileViolations) error {
+	type checkstyleError struct {
+		Line     int    `xml:"line,attr"`
+		Column   int    `xml:"column,attr"`
+		Severity string `xml:"severity,attr"`
+		Message  string `xml:"message,attr"`
+		Source   string `xml:"source,attr"`
+	}
+
+	type checkstyleFile struct {
+		Name   string            `xml:"name,attr"`
+		Errors []checkstyleError `xml:"error"`
+	}
+
+	type checkstyle struct {
+		XMLName xml.Name         `xml:"checkstyle"`
+		Version string           `xml:"version,attr"`
+		Files   []checkstyleFile `xml:"file"`
+	}
+
+	report := checkstyle{Version: "4.3"}
+
+	for _, result := range results {
+		file := checkstyleFile{Name: result.Filename}
+
+		for _, v := range result.Violations {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     v.Line,
+				Column:   v.Column,
+				Severity: "error",
+				Message:  v.Message,
+				Source:   "editorconfig." + v.Rule,
+			})
+		}
+
+		report.Files = append(report.Files, file)
+	}
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal the report: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s%s\n", xml.Header, data); err != nil {
+		return fmt.Errorf("cannot write the report: %w", err)
+	}
+
+	return nil
+}
+
+// writeSARIF writes the violations in the Static Analysis Results
+// Interchange Format, version 2.1.0.
+func writeSARIF(w io.Writer, results []fileViolations) error {
+	type sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+	}
+
+	type sarifLocation struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region *sarifRegion `json:"region,omitempty"`
+		} `json:"physicalLocation"`
+	}
+
+	type sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	type sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+
+	type sarifRule struct {
+		ID string `json:"id"`
+	}
+
+	type sarifRun struct {
+		Tool struct {
+			Driver struct {
+				Name           string      `json:"name"`
+				InformationURI string      `json:"informationUri"`
+				Version        string      `json:"version"`
+				Rules          []sarifRule `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	run := sarifRun{
+		Results: make([]sarifResult, 0),
+	}
+
+	run.Tool.Driver.Name = "editorconfig"
+	run.Tool.Driver.InformationURI = "https://editorconfig.org"
+	run.Tool.Driver.Version = version
+	run.Tool.Driver.Rules = make([]sarifRule, 0)
+
+	rules := make(map[string]bool)
+
+	for _, result := range results {
+		for _, v := range result.Violations {
+			rules[v.Rule] = true
+
+			var location sarifLocation
+
+			location.PhysicalLocation.ArtifactLocation.URI = filepath.ToSlash(result.Filename)
+
+			if v.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: v.Line, StartColumn: v.Column}
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    v.Rule,
+				Level:     "error",
+				Message:   sarifMessage{Text: v.Message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	for rule := range rules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+	}
+
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	return writeJSON(w, map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs":    []sarifRun{run},
+	})
+}
+
+// writeJSON writes the value as indented JSON.
+func writeJSON(w io.Writer, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal the report: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return fmt.Errorf("cannot write the report: %w", err)
+	}
+
+	return nil
+}
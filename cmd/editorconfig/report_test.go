@@ -0,0 +1,138 @@
+package main //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/checker"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func reportResults() []fileViolations {
+	return []fileViolations{
+		{
+			Filename: "a.go",
+			Violations: []checker.Violation{
+				{Line: 3, Column: 5, Rule: "trim_trailing_whitespace", Message: "trailing whitespace"},
+			},
+		},
+		{
+			Filename: "b.txt",
+			Violations: []checker.Violation{
+				{Rule: "insert_final_newline", Message: "missing final newline"},
+			},
+		},
+	}
+}
+
+func TestReporters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		format   string
+		results  []fileViolations
+		expected string
+	}{
+		{reportPlain, reportResults(), "a.go:3:5: trim_trailing_whitespace: trailing whitespace\n" +
+			"b.txt:0:0: insert_final_newline: missing final newline\n"},
+		{reportPlain, nil, ""},
+		{reportJSON, reportResults(), `[
+  {
+    "filename": "a.go",
+    "line": 3,
+    "column": 5,
+    "rule": "trim_trailing_whitespace",
+    "message": "trailing whitespace"
+  },
+  {
+    "filename": "b.txt",
+    "line": 0,
+    "column": 0,
+    "rule": "insert_final_newline",
+    "message": "missing final newline"
+  }
+]
+`},
+		{reportJSON, nil, "[]\n"},
+		{reportCheckstyle, reportResults(), `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3">
+  <file name="a.go">
+    <error line="3" column="5" severity="error" message="trailing whitespace" source="editorconfig.trim_trailing_whitespace"></error>
+  </file>
+  <file name="b.txt">
+    <error line="0" column="0" severity="error" message="missing final newline" source="editorconfig.insert_final_newline"></error>
+  </file>
+</checkstyle>
+`},
+		{reportCheckstyle, nil, `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="4.3"></checkstyle>
+`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+
+			assert.Nil(t, reporters[test.format](buf, test.results))
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	assert.Nil(t, writeSARIF(buf, reportResults()))
+
+	type region struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+	}
+
+	var report struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *region `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "2.1.0", report.Version)
+	assert.Equal(t, 1, len(report.Runs))
+
+	run := report.Runs[0]
+	assert.Equal(t, "editorconfig", run.Tool.Driver.Name)
+	assert.Equal(t, 2, len(run.Tool.Driver.Rules))
+	assert.Equal(t, "insert_final_newline", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "trim_trailing_whitespace", run.Tool.Driver.Rules[1].ID)
+
+	assert.Equal(t, 2, len(run.Results))
+	assert.Equal(t, "trim_trailing_whitespace", run.Results[0].RuleID)
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "a.go", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &region{StartLine: 3, StartColumn: 5}, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, (*region)(nil), run.Results[1].Locations[0].PhysicalLocation.Region)
+}
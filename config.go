@@ -3,15 +3,21 @@ package editorconfig
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"golang.org/x/mod/semver"
 )
 
-// ErrInvalidVersion represents a standard error with the semantic version.
-var ErrInvalidVersion = errors.New("invalid semantic version")
+var (
+	// ErrInvalidVersion represents a standard error with the semantic version.
+	ErrInvalidVersion = errors.New("invalid semantic version")
+	// ErrFSNotSupported is returned when the parser cannot read from a fs.FS.
+	ErrFSNotSupported = errors.New("parser does not support fs.FS")
+)
 
 // Config holds the configuration.
 type Config struct {
@@ -20,6 +26,12 @@ type Config struct {
 	Version  string
 	Parser   Parser
 	Graceful bool
+
+	// FS, when set, is where the .editorconfig files are read from instead
+	// of the operating system. The filenames given to Load are then
+	// slash-separated paths within FS, see fs.ValidPath, and the Parser must
+	// implement FSParser.
+	FS fs.FS
 }
 
 // Load loads definition of a given file.
@@ -33,24 +45,77 @@ func (config *Config) Load(filename string) (*Definition, error) {
 }
 
 // LoadGraceful loads definition of a given file with warnings and error.
-func (config *Config) LoadGraceful(filename string) (*Definition, error, error) { //nolint:funlen
-	// idiomatic go allows empty struct
-	if config.Parser == nil {
-		config.Parser = new(SimpleParser)
+func (config *Config) LoadGraceful(filename string) (*Definition, error, error) {
+	definition, _, warning, err := config.loadGraceful(filename)
+
+	return definition, warning, err
+}
+
+// loadGraceful loads the definition of a given file, and returns the
+// configuration files looked for, existing or not, from the closest one up to
+// the one with root = true.
+func (config *Config) loadGraceful(filename string) (*Definition, []string, error, error) {
+	absFilename, parse, err := config.resolve(filename)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	empty := (*Definition)(nil)
+	ecFile := config.ConfigName()
 
-	absFilename, err := filepath.Abs(filename)
+	definition, err := config.newDefinition()
 	if err != nil {
-		return empty, nil, fmt.Errorf("cannot get absolute path for %q: %w", filename, err)
+		return nil, nil, nil, err
+	}
+
+	var (
+		configs []string
+		warning error
+	)
+
+	dir := absFilename
+	for dir != filepath.Dir(dir) {
+		dir = filepath.Dir(dir)
+
+		name := filepath.Join(dir, ecFile)
+		configs = append(configs, name)
+
+		ec, warn, err := parse(name)
+		if warn != nil {
+			warning = errors.Join(warning, warn)
+		}
+
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, nil, nil, fmt.Errorf("cannot parse the ini file %q: %w", ecFile, err)
+		}
+
+		if err := config.merge(definition, ec, dir, absFilename); err != nil {
+			return nil, nil, nil, err
+		}
+
+		if ec.Root {
+			break
+		}
 	}
 
-	ecFile := config.Name
-	if ecFile == "" {
-		ecFile = ConfigNameDefault
+	return definition, configs, warning, nil
+}
+
+// ConfigName returns the name of the configuration files, Name or the
+// default one.
+func (config *Config) ConfigName() string {
+	if config.Name == "" {
+		return ConfigNameDefault
 	}
 
+	return config.Name
+}
+
+// newDefinition creates an empty definition for the configured version.
+func (config *Config) newDefinition() (*Definition, error) {
 	definition := &Definition{}
 	definition.Raw = make(map[string]string)
 
@@ -61,53 +126,73 @@ func (config *Config) LoadGraceful(filename string) (*Definition, error, error)
 		}
 
 		if ok := semver.IsValid(version); !ok {
-			return empty, nil, fmt.Errorf("version %s error: %w", config.Version, ErrInvalidVersion)
+			return nil, fmt.Errorf("version %s error: %w", config.Version, ErrInvalidVersion)
 		}
 
 		definition.version = version
 	}
 
-	var warning error
+	return definition, nil
+}
 
-	dir := absFilename
-	for dir != filepath.Dir(dir) {
-		dir = filepath.Dir(dir)
+// merge merges into the definition the one the Editorconfig, found in dir,
+// gives to the file.
+func (config *Config) merge(definition *Definition, ec *Editorconfig, dir string, absFilename string) error {
+	// give it the current config, without altering the one of the parser
+	// as it may be cached and shared.
+	ec = ec.withConfig(config)
 
-		ec, warn, err := config.Parser.ParseIniGraceful(filepath.Join(dir, ecFile))
-		if warn != nil {
-			warning = errors.Join(warning, warn)
-		}
+	relativeFilename := absFilename
+	if len(dir) < len(relativeFilename) {
+		relativeFilename = relativeFilename[len(dir):]
+	}
 
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				continue
-			}
+	// turn any Windows-y filename into the standard forward slash ones.
+	relativeFilename = filepath.ToSlash(relativeFilename)
 
-			return empty, nil, fmt.Errorf("cannot parse the ini file %q: %w", ecFile, err)
-		}
+	def, err := ec.GetDefinitionForFilename(relativeFilename)
+	if err != nil {
+		return fmt.Errorf("cannot get definition for %q: %w", relativeFilename, err)
+	}
 
-		// give it the current config.
-		ec.config = config
+	definition.merge(def)
 
-		relativeFilename := absFilename
-		if len(dir) < len(relativeFilename) {
-			relativeFilename = relativeFilename[len(dir):]
-		}
+	return nil
+}
 
-		// turn any Windows-y filename into the standard forward slash ones.
-		relativeFilename = filepath.ToSlash(relativeFilename)
+// resolve returns the absolute version of the filename and the function
+// parsing the configuration files found along its path.
+//
+// When reading from the FS, the filename is made absolute by prefixing it
+// with a slash, which is removed again before opening a file.
+func (config *Config) resolve(filename string) (string, func(string) (*Editorconfig, error, error), error) {
+	// idiomatic go allows empty struct
+	configParser := config.Parser
+	if configParser == nil {
+		configParser = new(SimpleParser)
+	}
 
-		def, err := ec.GetDefinitionForFilename(relativeFilename)
+	if config.FS == nil {
+		absFilename, err := filepath.Abs(filename)
 		if err != nil {
-			return empty, nil, fmt.Errorf("cannot get definition for %q: %w", relativeFilename, err)
+			return "", nil, fmt.Errorf("cannot get absolute path for %q: %w", filename, err)
 		}
 
-		definition.merge(def)
+		return absFilename, configParser.ParseIniGraceful, nil
+	}
 
-		if ec.Root {
-			break
-		}
+	parser, ok := configParser.(FSParser)
+	if !ok {
+		return "", nil, fmt.Errorf("cannot read %q using %T: %w", filename, configParser, ErrFSNotSupported)
+	}
+
+	if !fs.ValidPath(filename) {
+		return "", nil, fmt.Errorf("invalid path %q: %w", filename, fs.ErrInvalid)
+	}
+
+	parse := func(name string) (*Editorconfig, error, error) {
+		return parser.ParseIniGracefulFS(config.FS, strings.TrimPrefix(filepath.ToSlash(name), "/"))
 	}
 
-	return definition, warning, nil
+	return path.Join("/", filename), parse, nil
 }
@@ -0,0 +1,67 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+// mapFS returns a new file system holding the files, by path.
+func mapFS(files map[string]string) fstest.MapFS {
+	fsys := make(fstest.MapFS, len(files))
+
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+
+	return fsys
+}
+
+// testFiles are the files of a project with a nested configuration.
+var testFiles = map[string]string{
+	".editorconfig":     "root = true\n\n[*]\nend_of_line = lf\n\n[*.go]\nindent_style = space\n",
+	"src/.editorconfig": "[*.go]\nindent_style = tab\nindent_size = 4\n",
+	"src/main.go":       "",
+}
+
+func TestConfigLoadFS(t *testing.T) {
+	t.Parallel()
+
+	for _, parser := range []Parser{new(SimpleParser), NewCachedParser()} {
+		config := &Config{
+			FS:     mapFS(testFiles),
+			Parser: parser,
+		}
+
+		def, err := config.Load("src/main.go")
+		assert.Nil(t, err)
+		assert.Equal(t, IndentStyleTab, def.IndentStyle)
+		assert.Equal(t, "4", def.IndentSize)
+		assert.Equal(t, EndOfLineLf, def.EndOfLine)
+
+		def, err = config.Load("main.go")
+		assert.Nil(t, err)
+		assert.Equal(t, IndentStyleSpaces, def.IndentStyle)
+		assert.Equal(t, EndOfLineLf, def.EndOfLine)
+	}
+}
+
+func TestConfigLoadFSErrors(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(testFiles)}
+
+	_, err := config.Load("/src/main.go")
+	assert.Equal(t, true, errors.Is(err, fs.ErrInvalid))
+
+	config = &Config{
+		FS:     mapFS(testFiles),
+		Parser: struct{ Parser }{new(SimpleParser)},
+	}
+
+	_, err = config.Load("src/main.go")
+	assert.Equal(t, true, errors.Is(err, ErrFSNotSupported))
+}
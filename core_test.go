@@ -0,0 +1,467 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/ini.v1"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+// coreTestDir is the editorconfig-core-test submodule, see the Makefile.
+const coreTestDir = "core-test"
+
+// coreTestVersion is the version of the command line built for the tests of
+// its interface, like the Makefile does.
+const coreTestVersion = "1.99.99"
+
+// coreTestMacros are the CMake functions defining the tests run in-process,
+// telling whether the source file is given with its full path rather than
+// relative to the directory of the CMakeLists.txt file.
+var coreTestMacros = map[string]bool{
+	"new_ec_test":              false,
+	"new_ec_test_multiline":    false,
+	"new_ec_test_full_ec_file": true,
+}
+
+// coreTestIgnored are the CMake commands which do not define tests.
+var coreTestIgnored = map[string]bool{
+	"add_subdirectory":       true,
+	"cmake_minimum_required": true,
+	"else":                   true,
+	"elseif":                 true,
+	"enable_testing":         true,
+	"endif":                  true,
+	"if":                     true,
+	"include":                true,
+	"message":                true,
+	"project":                true,
+	"set":                    true,
+}
+
+var errUnsupportedCMake = errors.New("unsupported CMake")
+
+// coreTest is a test of the editorconfig-core-test suite, running the
+// command line on a file and matching its output.
+type coreTest struct {
+	name    string
+	dir     string
+	ecFile  string
+	srcFile string
+	// args are the arguments of the tests of the command line interface,
+	// which are run against the built binary rather than in-process.
+	args []string
+	// regex is the expression the output must match, the test of the
+	// command line interface passing on a zero exit code when empty.
+	regex    string
+	willFail bool
+}
+
+// TestCoreTest runs the editorconfig-core-test suite in-process, falling
+// back to the converted subset of testdata when the submodule is missing.
+// The tests of the command line interface run the built binary.
+func TestCoreTest(t *testing.T) {
+	t.Parallel()
+
+	root := coreTestDir
+	if _, err := os.Stat(filepath.Join(root, "CMakeLists.txt")); err != nil {
+		root = filepath.Join("testdata", "core-test")
+	}
+
+	tests, err := readCoreTests(root)
+	assert.Nil(t, err)
+
+	if len(tests) == 0 {
+		t.Fatalf("no tests found in %q", root)
+	}
+
+	binDir := t.TempDir()
+	binary := sync.OnceValues(func() (string, error) {
+		return buildCoreTestCommand(binDir)
+	})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			re, err := regexp.Compile(test.regex)
+			if err != nil {
+				t.Fatalf("cannot compile %q: %s", test.regex, err)
+			}
+
+			var passed bool
+
+			if test.args != nil {
+				command, err := binary()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				passed = test.runCommand(t, command, re)
+			} else {
+				passed = test.run(t, re)
+			}
+
+			if passed == test.willFail {
+				t.Errorf("passed: %t, expected: %t", passed, !test.willFail)
+			}
+		})
+	}
+}
+
+// run loads the definition of the source file, and tells whether its output
+// matches in the order of the command line.
+func (test *coreTest) run(t *testing.T, re *regexp.Regexp) bool {
+	t.Helper()
+
+	config := &Config{
+		Name: test.ecFile,
+	}
+
+	filename := test.srcFile
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(test.dir, filename)
+	}
+
+	def, _, err := config.LoadGraceful(filename)
+	assert.Nil(t, err)
+
+	output := coreTestOutput(def)
+	if !re.MatchString(output) {
+		t.Logf("%q does not match %q", output, test.regex)
+
+		return false
+	}
+
+	return true
+}
+
+// runCommand runs the command line from the directory of the test, and
+// tells whether it succeeds, or whether its output matches when there is a
+// regular expression.
+func (test *coreTest) runCommand(t *testing.T, command string, re *regexp.Regexp) bool {
+	t.Helper()
+
+	cmd := exec.Command(command, test.args...)
+	cmd.Dir = test.dir
+
+	output, err := cmd.CombinedOutput()
+	if test.regex == "" {
+		return err == nil
+	}
+
+	if !re.Match(output) {
+		t.Logf("%q does not match %q", output, test.regex)
+
+		return false
+	}
+
+	return true
+}
+
+// buildCoreTestCommand builds the command line into the directory.
+func buildCoreTestCommand(dir string) (string, error) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		goTool = filepath.Join(runtime.GOROOT(), "bin", "go")
+	}
+
+	binary := filepath.Join(dir, "editorconfig")
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	output, err := exec.Command( //nolint:gosec
+		goTool, "build",
+		"-ldflags", "-X main.version="+coreTestVersion,
+		"-o", binary,
+		"./cmd/editorconfig",
+	).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("cannot build the command line: %w\n%s", err, output)
+	}
+
+	return binary, nil
+}
+
+// coreTestOutput formats the definition like the command line does for a
+// single file, the properties being sorted by name as the expected outputs
+// list them that way.
+func coreTestOutput(def *Definition) string {
+	iniFile := ini.Empty()
+
+	def.Selector = ini.DefaultSection
+	def.InsertToIniFile(iniFile)
+
+	keys := iniFile.Section(ini.DefaultSection).Keys()
+	slices.SortFunc(keys, func(a, b *ini.Key) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	var output strings.Builder
+
+	for _, key := range keys {
+		output.WriteString(key.Name() + "=" + key.Value() + "\n")
+	}
+
+	return output.String()
+}
+
+// readCoreTests reads the tests of all the CMakeLists.txt files of the tree.
+// The unknown commands and variables fail the reading, rather than the tests
+// they define being left out.
+func readCoreTests(root string) ([]*coreTest, error) {
+	var tests []*coreTest
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != "CMakeLists.txt" {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		dir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		fileTests, err := readCoreTestFile(string(data), dir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		tests = append(tests, fileTests...)
+
+		return nil
+	})
+
+	return tests, err //nolint:wrapcheck
+}
+
+// readCoreTestFile reads the tests of a CMakeLists.txt file, the tests of
+// its function definitions being skipped.
+func readCoreTestFile(data string, dir string) ([]*coreTest, error) { //nolint:cyclop,funlen
+	var (
+		tests    []*coreTest
+		byName   = make(map[string]*coreTest)
+		function = 0
+	)
+
+	for _, command := range parseCMake(data) {
+		name := strings.ToLower(command[0])
+
+		switch {
+		case name == "function":
+			function++
+
+			continue
+		case name == "endfunction":
+			function--
+
+			continue
+		case function > 0 || coreTestIgnored[name]:
+			continue
+		}
+
+		args := make([]string, 0, len(command)-1)
+
+		for _, arg := range command[1:] {
+			arg = strings.ReplaceAll(arg, "${CMAKE_CURRENT_SOURCE_DIR}", filepath.ToSlash(dir))
+
+			if strings.Contains(arg, "${") && arg != "${EDITORCONFIG_CMD}" {
+				return nil, fmt.Errorf("%w variable in %q", errUnsupportedCMake, arg)
+			}
+
+			args = append(args, arg)
+		}
+
+		var test *coreTest
+
+		if fullPath, ok := coreTestMacros[name]; ok {
+			if len(args) != 4 { //nolint:mnd
+				return nil, fmt.Errorf("%w arguments to %s: %q", errUnsupportedCMake, name, args)
+			}
+
+			test = &coreTest{name: args[0], dir: dir, ecFile: args[1], srcFile: args[2], regex: args[3]}
+
+			if fullPath {
+				test.srcFile = filepath.FromSlash(test.srcFile)
+			}
+		}
+
+		switch name {
+		case "add_test":
+			// add_test(name command args...) or add_test(NAME name COMMAND command args...)
+			if len(args) > 3 && args[0] == "NAME" && args[2] == "COMMAND" {
+				args = append(args[1:2], args[3:]...)
+			}
+
+			if len(args) < 2 || args[1] != "${EDITORCONFIG_CMD}" {
+				return nil, fmt.Errorf("%w test command: %q", errUnsupportedCMake, args)
+			}
+
+			test = &coreTest{name: args[0], dir: dir, args: append([]string{}, args[2:]...)}
+		case "set_tests_properties":
+			if err := setCoreTestProperties(byName, args); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if test == nil {
+			return nil, fmt.Errorf("%w command %s", errUnsupportedCMake, command[0])
+		}
+
+		tests = append(tests, test)
+		byName[test.name] = test
+	}
+
+	return tests, nil
+}
+
+// setCoreTestProperties applies set_tests_properties(names... PROPERTIES
+// key value...) to the tests.
+func setCoreTestProperties(byName map[string]*coreTest, args []string) error {
+	at := slices.Index(args, "PROPERTIES")
+	if at < 0 || (len(args)-at-1)%2 != 0 {
+		return fmt.Errorf("%w properties: %q", errUnsupportedCMake, args)
+	}
+
+	for _, name := range args[:at] {
+		test, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w properties of the unknown test %s", errUnsupportedCMake, name)
+		}
+
+		for i := at + 1; i < len(args); i += 2 {
+			switch key, value := args[i], args[i+1]; key {
+			case "PASS_REGULAR_EXPRESSION":
+				test.regex = value
+			case "WILL_FAIL":
+				test.willFail = strings.EqualFold(value, "true") || value == "1" || strings.EqualFold(value, "on")
+			default:
+				return fmt.Errorf("%w property %s of %s", errUnsupportedCMake, key, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// parseCMake returns the commands of a CMake file, as the name of the command
+// followed by its arguments.
+func parseCMake(data string) [][]string { //nolint:cyclop
+	var commands [][]string
+
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '#':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			start := i
+			for i < len(data) && data[i] != '(' && data[i] != '\n' {
+				i++
+			}
+
+			if i == len(data) || data[i] != '(' {
+				continue
+			}
+
+			command := []string{strings.TrimSpace(data[start:i])}
+
+			var args []string
+
+			args, i = parseCMakeArguments(data, i+1)
+
+			commands = append(commands, append(command, args...))
+		}
+	}
+
+	return commands
+}
+
+// parseCMakeArguments parses the arguments of a command up to the closing
+// parenthesis, returning the position of the latter.
+func parseCMakeArguments(data string, i int) ([]string, int) { //nolint:cyclop
+	var args []string
+
+	for ; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == ')':
+			return args, i
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case c == '#':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case c == '"':
+			var arg strings.Builder
+
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' && i+1 < len(data) {
+					i++
+					arg.WriteString(unescapeCMake(data[i]))
+
+					continue
+				}
+
+				arg.WriteByte(data[i])
+			}
+
+			args = append(args, arg.String())
+		default:
+			var arg strings.Builder
+
+			for ; i < len(data) && !strings.ContainsRune(" \t\r\n()", rune(data[i])); i++ {
+				if data[i] == '\\' && i+1 < len(data) {
+					i++
+					arg.WriteString(unescapeCMake(data[i]))
+
+					continue
+				}
+
+				arg.WriteByte(data[i])
+			}
+
+			i--
+
+			args = append(args, arg.String())
+		}
+	}
+
+	return args, i
+}
+
+// unescapeCMake returns the character escaped by a backslash.
+func unescapeCMake(c byte) string {
+	switch c {
+	case 't':
+		return "\t"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case ';':
+		return `\;`
+	default:
+		return string(c)
+	}
+}
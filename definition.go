@@ -1,6 +1,7 @@
 package editorconfig
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -20,12 +21,25 @@ type Definition struct {
 	Charset                string            `ini:"charset"      json:"charset,omitempty"`
 	IndentStyle            string            `ini:"indent_style" json:"indent_style,omitempty"`
 	IndentSize             string            `ini:"indent_size"  json:"indent_size,omitempty"`
-	TabWidth               int               `ini:"-"            json:"-"`
+	TabWidth               int               `ini:"-"            json:"tab_width,omitempty"`
 	EndOfLine              string            `ini:"end_of_line"  json:"end_of_line,omitempty"`
-	TrimTrailingWhitespace *bool             `ini:"-"            json:"-"`
-	InsertFinalNewline     *bool             `ini:"-"            json:"-"`
-	Raw                    map[string]string `ini:"-"            json:"-"`
+	TrimTrailingWhitespace *bool             `ini:"-"            json:"trim_trailing_whitespace,omitempty"`
+	InsertFinalNewline     *bool             `ini:"-"            json:"insert_final_newline,omitempty"`
+	MaxLineLength          int               `ini:"-"            json:"max_line_length,omitempty"`
+	SpellingLanguage       string            `ini:"-"            json:"spelling_language,omitempty"`
+	Raw                    map[string]string `ini:"-"            json:"raw,omitempty"`
 	version                string
+
+	// filename, line, lines, columns and keyColumns locate the section and
+	// its properties in its file, the columns being the ones of the values
+	// and the keys.
+	filename   string
+	line       int
+	lines      map[string]int
+	columns    map[string]int
+	keyColumns map[string]int
+	// origins are where the properties of a resolved definition come from.
+	origins map[string]*Origin
 }
 
 // NewDefinition builds a definition from a given config.
@@ -76,6 +90,17 @@ func (d *Definition) InsertToIniFile(iniFile *ini.File) { //nolint:funlen,gocogn
 			}
 		case "indent_size":
 			v = d.IndentSize
+		case "spelling_language":
+			if d.SpellingLanguage != "" {
+				v = d.SpellingLanguage
+			}
+		case "max_line_length":
+			switch {
+			case d.MaxLineLength == MaxLineLengthOff:
+				v = MaxLineLengthOffValue
+			case d.MaxLineLength > 0:
+				v = strconv.Itoa(d.MaxLineLength)
+			}
 		}
 
 		iniSec.NewKey(k, v) //nolint:errcheck
@@ -106,6 +131,52 @@ func (d *Definition) InsertToIniFile(iniFile *ini.File) { //nolint:funlen,gocogn
 	}
 }
 
+// MarshalJSON encodes the definition, max_line_length being "off" rather
+// than MaxLineLengthOff when the lines are not limited.
+func (d *Definition) MarshalJSON() ([]byte, error) {
+	type definition Definition
+
+	if d.MaxLineLength != MaxLineLengthOff {
+		return json.Marshal((*definition)(d)) //nolint:wrapcheck
+	}
+
+	return json.Marshal(struct { //nolint:wrapcheck
+		*definition
+
+		MaxLineLength string `json:"max_line_length"`
+	}{(*definition)(d), MaxLineLengthOffValue})
+}
+
+// UnmarshalJSON decodes the definition, max_line_length being either an
+// integer or "off".
+func (d *Definition) UnmarshalJSON(data []byte) error {
+	type definition Definition
+
+	value := struct {
+		*definition
+
+		MaxLineLength json.RawMessage `json:"max_line_length"`
+	}{definition: (*definition)(d)}
+
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	var off string
+
+	switch {
+	case value.MaxLineLength == nil:
+	case json.Unmarshal(value.MaxLineLength, &off) == nil && strings.EqualFold(off, MaxLineLengthOffValue):
+		d.MaxLineLength = MaxLineLengthOff
+	default:
+		if err := json.Unmarshal(value.MaxLineLength, &d.MaxLineLength); err != nil {
+			return fmt.Errorf("max_line_length=%s: %w", value.MaxLineLength, ErrInvalidValue)
+		}
+	}
+
+	return nil
+}
+
 // normalize fixes some values to their lowercase value.
 func (d *Definition) normalize() error {
 	var result error
@@ -118,10 +189,7 @@ func (d *Definition) normalize() error {
 	if ok && trimTrailingWhitespace != UnsetValue {
 		trim, err := strconv.ParseBool(trimTrailingWhitespace)
 		if err != nil {
-			result = errors.Join(
-				result,
-				fmt.Errorf("trim_trailing_whitespace=%s is not an acceptable value. %w", trimTrailingWhitespace, err),
-			)
+			result = errors.Join(result, d.warning("trim_trailing_whitespace", trimTrailingWhitespace, err))
 		} else {
 			d.TrimTrailingWhitespace = &trim
 		}
@@ -131,10 +199,7 @@ func (d *Definition) normalize() error {
 	if ok && insertFinalNewline != UnsetValue {
 		insert, err := strconv.ParseBool(insertFinalNewline)
 		if err != nil {
-			result = errors.Join(
-				result,
-				fmt.Errorf("insert_final_newline=%s is not an acceptable value. %w", insertFinalNewline, err),
-			)
+			result = errors.Join(result, d.warning("insert_final_newline", insertFinalNewline, err))
 		} else {
 			d.InsertFinalNewline = &insert
 		}
@@ -145,12 +210,40 @@ func (d *Definition) normalize() error {
 	if ok && tabWidth != UnsetValue {
 		num, err := strconv.Atoi(tabWidth)
 		if err != nil {
-			result = errors.Join(result, fmt.Errorf("tab_width=%s is not an acceptable value. %w", tabWidth, err))
+			result = errors.Join(result, d.warning("tab_width", tabWidth, err))
 		} else {
 			d.TabWidth = num
 		}
 	}
 
+	maxLineLength, ok := d.Raw["max_line_length"]
+	if ok && maxLineLength != UnsetValue {
+		if strings.EqualFold(maxLineLength, MaxLineLengthOffValue) {
+			d.MaxLineLength = MaxLineLengthOff
+		} else {
+			num, err := strconv.Atoi(maxLineLength)
+
+			switch {
+			case err != nil:
+				result = errors.Join(result, d.warning("max_line_length", maxLineLength, err))
+			case num <= 0:
+				result = errors.Join(result, d.warning("max_line_length", maxLineLength, ErrInvalidValue))
+			default:
+				d.MaxLineLength = num
+			}
+		}
+	}
+
+	spellingLanguage, ok := d.Raw["spelling_language"]
+	if ok && spellingLanguage != UnsetValue {
+		language, err := parseSpellingLanguage(spellingLanguage)
+		if err != nil {
+			result = errors.Join(result, d.warning("spelling_language", spellingLanguage, err))
+		} else {
+			d.SpellingLanguage = language
+		}
+	}
+
 	// tab_width defaults to indent_size:
 	// https://github.com/editorconfig/editorconfig/wiki/EditorConfig-Properties#tab_width
 	num, err := strconv.Atoi(d.IndentSize)
@@ -161,6 +254,17 @@ func (d *Definition) normalize() error {
 	return result
 }
 
+// warning builds the warning of a property having an unacceptable value.
+func (d *Definition) warning(key string, value string, err error) *ParseError {
+	return &ParseError{
+		Selector: d.Selector,
+		Key:      key,
+		Value:    value,
+		Severity: SeverityWarning,
+		Err:      fmt.Errorf("%s=%s is not an acceptable value. %w", key, value, err),
+	}
+}
+
 // merge the parent definition into the child definition.
 func (d *Definition) merge(md *Definition) {
 	if len(d.Charset) == 0 {
@@ -195,9 +299,24 @@ func (d *Definition) merge(md *Definition) {
 		}
 	}
 
+	if maxLineLength, ok := d.Raw["max_line_length"]; !ok || maxLineLength != UnsetValue {
+		if d.MaxLineLength == 0 {
+			d.MaxLineLength = md.MaxLineLength
+		}
+	}
+
+	if spellingLanguage, ok := d.Raw["spelling_language"]; !ok || spellingLanguage != UnsetValue {
+		if len(d.SpellingLanguage) == 0 {
+			d.SpellingLanguage = md.SpellingLanguage
+		}
+	}
+
 	for k, v := range md.Raw {
-		if _, ok := d.Raw[k]; !ok {
+		_, ok := d.Raw[k]
+		if !ok {
 			d.Raw[k] = v
 		}
+
+		d.trackOrigin(k, md, !ok)
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/editorconfig/editorconfig-core-go/v2/syntax"
 	"gopkg.in/ini.v1"
 )
 
@@ -31,6 +32,16 @@ const (
 	EndOfLineCrLf = "crlf"
 )
 
+// MaxLineLength special values.
+const (
+	// MaxLineLengthOff is the MaxLineLength of the lines not being limited,
+	// 0 meaning that the property is not set.
+	MaxLineLengthOff = -1
+	// MaxLineLengthOffValue is the value of max_line_length for the lines
+	// not being limited.
+	MaxLineLengthOffValue = "off"
+)
+
 // Charset possible values.
 const (
 	CharsetLatin1  = "latin1"
@@ -40,9 +51,11 @@ const (
 	CharsetUTF16LE = "utf-16le"
 )
 
-// Limit for section name.
+// Limits for the section names, the keys and the values.
 const (
 	MaxSectionLength = 4096
+	MaxKeyLength     = 1024
+	MaxValueLength   = 4096
 )
 
 // Editorconfig represents a .editorconfig file.
@@ -53,51 +66,172 @@ type Editorconfig struct {
 	Root        bool
 	Definitions []*Definition
 	config      *Config
+
+	// duplicates are the properties set more than once in a section.
+	duplicates []*ParseError
 }
 
-// newEditorconfig builds the configuration from an INI file.
-func newEditorconfig(iniFile *ini.File) (*Editorconfig, error, error) {
+// newEditorconfig builds the configuration from the syntax tree of a file,
+// following the EditorConfig file format. The invalid lines, the sections and
+// the properties exceeding the limits are ignored with a warning.
+func newEditorconfig(f *syntax.File, filename string) (*Editorconfig, error) {
 	editorConfig := &Editorconfig{}
 
 	var warning error
 
-	// Consider mixed-case values for true and false.
-	rootKey := iniFile.Section(ini.DefaultSection).Key("root")
-	rootKey.SetValue(strings.ToLower(rootKey.Value()))
-	editorConfig.Root = rootKey.MustBool(false)
+	// root is only valid in the preamble, and is case insensitive.
+	if root, ok := f.Preamble.Get("root"); ok {
+		editorConfig.Root = strings.EqualFold(root, "true")
+	}
+
+	warning = errors.Join(warning, invalidLines(f.Preamble, filename))
+
+	for _, section := range f.Sections {
+		selector := section.Name()
+
+		if len(selector) > MaxSectionLength {
+			warning = errors.Join(warning, &ParseError{
+				Filename: filename,
+				Line:     section.Header.Num(),
+				Column:   1,
+				Selector: selector,
+				Severity: SeverityWarning,
+				Err:      fmt.Errorf("section of %d characters: %w", len(selector), ErrTooLong),
+			})
 
-	for _, sectionStr := range iniFile.SectionStrings() {
-		if sectionStr == ini.DefaultSection || len(sectionStr) > MaxSectionLength {
 			continue
 		}
 
-		iniSection := iniFile.Section(sectionStr)
-		definition := &Definition{}
-		raw := make(map[string]string)
-
-		err := iniSection.MapTo(&definition)
-		if err != nil {
-			return editorConfig, nil, fmt.Errorf("error mapping current section: %w", err)
+		definition := &Definition{
+			Selector:   selector,
+			Raw:        make(map[string]string),
+			filename:   filename,
+			line:       section.Header.Num(),
+			lines:      make(map[string]int),
+			columns:    make(map[string]int),
+			keyColumns: make(map[string]int),
 		}
 
-		// Shallow copy all the properties
-		for k, v := range iniSection.KeysHash() {
-			raw[strings.ToLower(k)] = v
+		for _, l := range section.Properties() {
+			key := strings.ToLower(l.Key())
+			value := l.Value()
+
+			if err := checkProperty(key, value); err != nil {
+				start, _ := l.KeyRange()
+
+				warning = errors.Join(warning, &ParseError{
+					Filename: filename,
+					Line:     l.Num(),
+					Column:   start + 1,
+					Selector: selector,
+					Key:      key,
+					Value:    value,
+					Severity: SeverityWarning,
+					Err:      err,
+				})
+
+				continue
+			}
+
+			keyStart, _ := l.KeyRange()
+
+			if line, ok := definition.lines[key]; ok {
+				editorConfig.duplicates = append(editorConfig.duplicates, &ParseError{
+					Filename: filename,
+					Line:     l.Num(),
+					Column:   keyStart + 1,
+					Selector: selector,
+					Key:      key,
+					Value:    value,
+					Severity: SeverityWarning,
+					Err:      fmt.Errorf("%s already set on line %d: %w", key, line, ErrDuplicateKey),
+				})
+			}
+
+			start, _ := l.ValueRange()
+
+			definition.Raw[key] = value
+			definition.lines[key] = l.Num()
+			definition.columns[key] = start + 1
+			definition.keyColumns[key] = keyStart + 1
 		}
 
-		definition.Raw = raw
-		definition.Selector = sectionStr
+		definition.Charset = definition.Raw["charset"]
+		definition.IndentSize = definition.Raw["indent_size"]
+
+		if err := definition.normalize(); err != nil {
+			for _, pe := range ParseErrors(err) {
+				pe.Filename = filename
+				pe.Line = definition.lines[pe.Key]
+				pe.Column = definition.columns[pe.Key]
+			}
 
-		err = definition.normalize()
-		if err != nil {
 			// Append those error(s) into the warning
 			warning = errors.Join(warning, err)
 		}
 
+		warning = errors.Join(warning, invalidLines(section, filename))
+
 		editorConfig.Definitions = append(editorConfig.Definitions, definition)
 	}
 
-	return editorConfig, warning, nil
+	return editorConfig, warning
+}
+
+// checkProperty verifies the length of the key and the value of a property.
+func checkProperty(key string, value string) error {
+	if len(key) > MaxKeyLength {
+		return fmt.Errorf("key of %d characters: %w", len(key), ErrTooLong)
+	}
+
+	if len(value) > MaxValueLength {
+		return fmt.Errorf("%s value of %d characters: %w", key, len(value), ErrTooLong)
+	}
+
+	return nil
+}
+
+// invalidLines returns the warnings of the lines of the section which are
+// neither blank, comments nor properties.
+func invalidLines(section *syntax.Section, filename string) error {
+	var warning error
+
+	for _, l := range section.Lines {
+		if l.Kind() != syntax.Invalid {
+			continue
+		}
+
+		text := l.Text()
+		trimmed := strings.TrimSpace(text)
+
+		warning = errors.Join(warning, &ParseError{
+			Filename: filename,
+			Line:     l.Num(),
+			Column:   strings.Index(text, trimmed) + 1,
+			Selector: section.Name(),
+			Severity: SeverityWarning,
+			Err:      fmt.Errorf("%q: %w", trimmed, ErrInvalidLine),
+		})
+	}
+
+	return warning
+}
+
+// loadEditorconfig builds the configuration from the content of a file, the
+// filename being used to locate the properties and the warnings.
+func loadEditorconfig(data []byte, filename string) (*Editorconfig, error, error) {
+	f, err := syntax.Parse(bytes.NewReader(data))
+	if err != nil {
+		return &Editorconfig{}, nil, &ParseError{
+			Filename: filename,
+			Severity: SeverityError,
+			Err:      fmt.Errorf("cannot parse: %w", err),
+		}
+	}
+
+	ec, warning := newEditorconfig(f, filename)
+
+	return ec, warning, nil
 }
 
 // GetDefinitionForFilename returns a definition for the given filename.
@@ -112,21 +246,8 @@ func (e *Editorconfig) GetDefinitionForFilename(name string) (*Definition, error
 	// The last section has preference over the priors.
 	for i := len(e.Definitions) - 1; i >= 0; i-- {
 		actualDef := e.Definitions[i]
-		selector := actualDef.Selector
 
-		if !strings.HasPrefix(selector, "/") {
-			if strings.ContainsRune(selector, '/') {
-				selector = "/" + selector
-			} else {
-				selector = "/**/" + selector
-			}
-		}
-
-		if !strings.HasPrefix(name, "/") {
-			name = "/" + name
-		}
-
-		ok, err := e.FnmatchCase(selector, name)
+		ok, err := e.FnmatchCase(anchorSelector(actualDef.Selector), anchorFilename(name))
 		if err != nil {
 			return nil, err
 		}
@@ -139,6 +260,14 @@ func (e *Editorconfig) GetDefinitionForFilename(name string) (*Definition, error
 	return def, nil
 }
 
+// withConfig returns a shallow copy of the Editorconfig using the config.
+func (e *Editorconfig) withConfig(config *Config) *Editorconfig {
+	ec := *e
+	ec.config = config
+
+	return &ec
+}
+
 // FnmatchCase calls the matcher from the config's parser or the vanilla's.
 func (e *Editorconfig) FnmatchCase(selector string, filename string) (bool, error) {
 	if e.config != nil && e.config.Parser != nil {
@@ -167,6 +296,10 @@ func (e *Editorconfig) Serialize() ([]byte, error) {
 }
 
 // Write writes the Editorconfig to the Writer in a compatible INI file.
+//
+// The file is rebuilt from the definitions, losing the comments, the blank
+// lines and the formatting of a parsed file. Only the syntax.File of the
+// syntax package writes back a parsed file unchanged.
 func (e *Editorconfig) Write(w io.Writer) error {
 	iniFile := ini.Empty()
 
@@ -188,7 +321,8 @@ func (e *Editorconfig) Write(w io.Writer) error {
 	return nil
 }
 
-// Save saves the Editorconfig to a compatible INI file.
+// Save saves the Editorconfig to a compatible INI file, rebuilt like Write
+// does.
 func (e *Editorconfig) Save(filename string) error {
 	f, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
 	if err != nil {
@@ -208,56 +342,50 @@ func boolToString(b bool) string {
 
 // Parse parses from a reader.
 func Parse(r io.Reader) (*Editorconfig, error) {
-	iniFile, err := ini.Load(r)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return nil, fmt.Errorf("cannot load ini file: %w", err)
-	}
-
-	ec, warning, err := newEditorconfig(iniFile)
-	if warning != nil {
-		err = errors.Join(warning, err)
+		return nil, fmt.Errorf("cannot read: %w", err)
 	}
 
-	return ec, err
+	return parse(data, "")
 }
 
 // ParseGraceful parses from a reader with warnings not treated as a fatal error.
 func ParseGraceful(r io.Reader) (*Editorconfig, error, error) {
-	iniFile, err := ini.Load(r)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return &Editorconfig{}, nil, fmt.Errorf("cannot load ini file: %w", err)
+		return &Editorconfig{}, nil, fmt.Errorf("cannot read: %w", err)
 	}
 
-	return newEditorconfig(iniFile)
+	return loadEditorconfig(data, "")
 }
 
 // ParseBytes parses from a slice of bytes.
 //
 // Deprecated: use Parse instead.
 func ParseBytes(data []byte) (*Editorconfig, error) {
-	iniFile, err := ini.Load(data)
-	if err != nil {
-		return nil, fmt.Errorf("cannot load ini file: %w", err)
-	}
-
-	ec, warning, err := newEditorconfig(iniFile)
-	if warning != nil {
-		err = errors.Join(warning, err)
-	}
-
-	return ec, err
+	return parse(data, "")
 }
 
 // ParseFile parses from a file.
 //
 // Deprecated: use Parse instead.
 func ParseFile(path string) (*Editorconfig, error) {
-	iniFile, err := ini.Load(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %q: %w", path, err)
+	}
+
+	return parse(data, path)
+}
+
+// parse builds the configuration with the warnings joined to the error.
+func parse(data []byte, filename string) (*Editorconfig, error) {
+	ec, warning, err := loadEditorconfig(data, filename)
 	if err != nil {
-		return nil, fmt.Errorf("cannot load ini file: %w", err)
+		return nil, err
 	}
 
-	ec, warning, err := newEditorconfig(iniFile)
 	if warning != nil {
 		err = errors.Join(warning, err)
 	}
@@ -1,9 +1,12 @@
 package editorconfig //nolint:testpackage
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
 	"testing/iotest"
@@ -177,3 +180,239 @@ func TestPublicTestDefinitionForFilenameWithConfigname(t *testing.T) {
 	assert.Equal(t, false, *def.InsertFinalNewline)
 	assert.Equal(t, false, *def.TrimTrailingWhitespace)
 }
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	_, warning, err := GetDefinitionForFilenameWithConfignameGraceful("testdata/root/src/dummy.go", "a.ini")
+	assert.Nil(t, err)
+
+	errs := ParseErrors(warning)
+	assert.Equal(t, 3, len(errs))
+
+	var pe *ParseError
+	assert.Equal(t, true, errors.As(warning, &pe))
+
+	for _, pe := range errs {
+		assert.Equal(t, SeverityWarning, pe.Severity)
+		assert.Equal(t, "invalid", pe.Selector)
+		assert.Equal(t, "off", pe.Value)
+		assert.Equal(t, true, strings.HasSuffix(pe.Filename, filepath.Join("testdata", "a.ini")))
+		assert.Equal(t, true, errors.Is(pe, strconv.ErrSyntax))
+
+		switch pe.Key {
+		case "insert_final_newline":
+			assert.Equal(t, 20, pe.Line)
+			assert.Equal(t, 24, pe.Column)
+		case "trim_trailing_whitespace":
+			assert.Equal(t, 21, pe.Line)
+			assert.Equal(t, 28, pe.Column)
+		case "tab_width":
+			assert.Equal(t, 22, pe.Line)
+			assert.Equal(t, 13, pe.Column)
+		default:
+			t.Errorf("unexpected key %q", pe.Key)
+		}
+	}
+}
+
+func TestParseInvalidLine(t *testing.T) {
+	t.Parallel()
+
+	ec, warning, err := ParseGraceful(strings.NewReader("[*\n  key\n[*.go]\nindent_style = tab\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ec.Definitions))
+
+	errs := ParseErrors(warning)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, true, errors.Is(errs[0], ErrInvalidLine))
+	assert.Equal(t, 1, errs[0].Line)
+	assert.Equal(t, 2, errs[1].Line)
+	assert.Equal(t, 3, errs[1].Column)
+	assert.Equal(t, SeverityWarning, errs[1].Severity)
+}
+
+func TestParseSpec(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"ROOT = True",
+		"[*]",
+		"root = false",
+		"indent_style = space ; not a comment",
+		"[*.go]",
+		"Indent_Style = tab",
+		"[*]",
+		"indent_style = \"tab\"",
+		"# a comment",
+		"; another one",
+		"key = a=b",
+		"[" + strings.Repeat("a", MaxSectionLength+1) + "]",
+		"indent_size = 2",
+		"[*.md]",
+		strings.Repeat("k", MaxKeyLength+1) + " = v",
+		"long = " + strings.Repeat("v", MaxValueLength+1),
+	}, "\n")
+
+	ec, warning, err := ParseGraceful(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, true, ec.Root)
+	assert.Equal(t, 3, len(ParseErrors(warning)))
+
+	// the sections sharing the same name are kept apart.
+	assert.Equal(t, 4, len(ec.Definitions))
+	assert.Equal(t, "space ; not a comment", ec.Definitions[0].Raw["indent_style"])
+	assert.Equal(t, "false", ec.Definitions[0].Raw["root"])
+	assert.Equal(t, "tab", ec.Definitions[1].IndentStyle)
+	assert.Equal(t, "\"tab\"", ec.Definitions[2].Raw["indent_style"])
+	assert.Equal(t, "a=b", ec.Definitions[2].Raw["key"])
+	assert.Equal(t, 0, len(ec.Definitions[3].Raw))
+
+	def, err := ec.GetDefinitionForFilename("main.go")
+	assert.Nil(t, err)
+	assert.Equal(t, "\"tab\"", def.IndentStyle)
+}
+
+func TestMaxLineLength(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"[*]",
+		"max_line_length = 80",
+		"[*.md]",
+		"max_line_length = OFF",
+		"[*.txt]",
+		"max_line_length = unset",
+		"[*.go]",
+		"max_line_length = -1",
+	}, "\n")
+
+	ec, warning, err := ParseGraceful(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ParseErrors(warning)))
+
+	tests := []struct {
+		filename string
+		expected int
+	}{
+		{"main.c", 80},
+		{"README.md", MaxLineLengthOff},
+		{"notes.txt", 0},
+		{"main.go", 80},
+	}
+
+	for _, test := range tests {
+		def, err := ec.GetDefinitionForFilename(test.filename)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, def.MaxLineLength)
+	}
+
+	serialized, err := ec.Serialize()
+	assert.Nil(t, err)
+
+	// the invalid value is kept as is.
+	saved, _, err := ParseGraceful(bytes.NewReader(serialized))
+	assert.Nil(t, err)
+
+	def, err := saved.GetDefinitionForFilename("README.md")
+	assert.Nil(t, err)
+	assert.Equal(t, MaxLineLengthOff, def.MaxLineLength)
+	assert.Equal(t, MaxLineLengthOffValue, def.Raw["max_line_length"])
+}
+
+func TestSpellingLanguage(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"[*]",
+		"spelling_language = en-us",
+		"[*.fr.md]",
+		"spelling_language = FR",
+		"[*.txt]",
+		"spelling_language = unset",
+		"[*.go]",
+		"spelling_language = english",
+	}, "\n")
+
+	ec, warning, err := ParseGraceful(strings.NewReader(data))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ParseErrors(warning)))
+
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"README.md", "en-US"},
+		{"README.fr.md", "fr"},
+		{"notes.txt", ""},
+		{"main.go", "en-US"},
+	}
+
+	for _, test := range tests {
+		def, err := ec.GetDefinitionForFilename(test.filename)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, def.SpellingLanguage)
+	}
+
+	serialized, err := ec.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, true, bytes.Contains(serialized, []byte("spelling_language = en-US")))
+}
+
+func TestDefinitionJSON(t *testing.T) {
+	t.Parallel()
+
+	def, err := GetDefinitionForFilename("testdata/root/src/dummy.go")
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(def)
+	assert.Nil(t, err)
+
+	var decoded map[string]any
+
+	err = json.Unmarshal(data, &decoded)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(4), decoded["tab_width"])
+	assert.Equal(t, true, decoded["insert_final_newline"])
+	assert.Equal(t, map[string]any{
+		"indent_size":          "4",
+		"indent_style":         "tab",
+		"insert_final_newline": "true",
+	}, decoded["raw"])
+
+	var roundtrip Definition
+
+	err = json.Unmarshal(data, &roundtrip)
+	assert.Nil(t, err)
+	assert.Equal(t, true, equalDefinitions(def, &roundtrip))
+}
+
+func TestDefinitionJSONMaxLineLength(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		maxLineLength int
+		expected      string
+	}{
+		{MaxLineLengthOff, `{"max_line_length":"off"}`},
+		{80, `{"max_line_length":80}`},
+		{0, `{}`},
+	}
+
+	for _, test := range tests {
+		data, err := json.Marshal(&Definition{MaxLineLength: test.maxLineLength})
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, string(data))
+
+		var decoded Definition
+
+		err = json.Unmarshal(data, &decoded)
+		assert.Nil(t, err)
+		assert.Equal(t, test.maxLineLength, decoded.MaxLineLength)
+	}
+
+	var decoded Definition
+
+	err := json.Unmarshal([]byte(`{"max_line_length":"long"}`), &decoded)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidValue))
+}
@@ -0,0 +1,105 @@
+package editorconfig
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidLine is a line which is neither a section header, a property,
+	// a comment nor a blank line.
+	ErrInvalidLine = errors.New("invalid line")
+	// ErrTooLong is a section name, a key or a value exceeding its limit.
+	ErrTooLong = errors.New("too long")
+)
+
+// Severity tells whether a ParseError prevents the file from being used.
+type Severity int
+
+// Severity possible values.
+const (
+	// SeverityError is a fatal error, the file cannot be used.
+	SeverityError Severity = iota
+	// SeverityWarning is a non-fatal error, e.g. a property having an invalid
+	// value which is ignored.
+	SeverityWarning
+)
+
+// String returns "error" or "warning".
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+
+	return "error"
+}
+
+// ParseError is a problem found in a .editorconfig file.
+//
+// The warnings and errors returned by the parsers wrap ParseError values,
+// which can be retrieved using errors.As or ParseErrors.
+type ParseError struct {
+	// Filename is the path of the .editorconfig file, empty when parsed from
+	// a reader.
+	Filename string
+	// Line and Column locate the problem, starting at 1, 0 when unknown.
+	Line   int
+	Column int
+	// Selector is the section of the file, empty for the preamble.
+	Selector string
+	// Key and Value are the offending property, if any.
+	Key   string
+	Value string
+
+	Severity Severity
+	Err      error
+}
+
+// Error returns the message prefixed by the position, e.g.
+// "path/to/.editorconfig:3:16: indent_size=x is not an acceptable value.".
+func (e *ParseError) Error() string {
+	var prefix []string
+
+	if e.Filename != "" {
+		prefix = append(prefix, e.Filename)
+	}
+
+	if e.Line > 0 {
+		prefix = append(prefix, strconv.Itoa(e.Line))
+
+		if e.Column > 0 {
+			prefix = append(prefix, strconv.Itoa(e.Column))
+		}
+	}
+
+	if len(prefix) == 0 {
+		return e.Err.Error()
+	}
+
+	return strings.Join(prefix, ":") + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors returns all the ParseError values wrapped by err, e.g. the
+// ones joined into the warning of a graceful parsing.
+func ParseErrors(err error) []*ParseError {
+	var result []*ParseError
+
+	switch e := err.(type) { //nolint:errorlint
+	case *ParseError:
+		result = append(result, e)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			result = append(result, ParseErrors(err)...)
+		}
+	case interface{ Unwrap() error }:
+		result = append(result, ParseErrors(e.Unwrap())...)
+	}
+
+	return result
+}
@@ -0,0 +1,338 @@
+// Package fixer rewrites content to follow the rules of its editorconfig
+// definition.
+package fixer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// defaultTabWidth is used when neither tab_width nor indent_size are set.
+const defaultTabWidth = 8
+
+var (
+	// ErrUnsupportedCharset is returned when the content cannot be rewritten
+	// line by line, e.g. for UTF-16.
+	ErrUnsupportedCharset = errors.New("unsupported charset")
+	// ErrClosed is returned when writing to a closed Writer.
+	ErrClosed = errors.New("write to closed fixer")
+)
+
+var bomUTF8 = []byte{0xef, 0xbb, 0xbf}
+
+// Fix copies the content of src into dst while rewriting it to follow the
+// rules of the definition.
+func Fix(def *editorconfig.Definition, dst io.Writer, src io.Reader) error {
+	w := NewWriter(def, dst)
+
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("cannot fix content: %w", err)
+	}
+
+	return w.Close()
+}
+
+// Writer rewrites the content written to it before passing it on.
+//
+// The lines are written as soon as they are complete, the end of the file,
+// e.g. the final newline, is only handled when Close is called.
+type Writer struct {
+	def      *editorconfig.Definition
+	w        io.Writer
+	tabWidth int
+	eol      []byte
+
+	started bool
+	closed  bool
+	hadBOM  bool
+	buf     []byte
+	// held are the line terminators not written yet as they may end the file.
+	held [][]byte
+}
+
+// NewWriter returns a Writer rewriting the content into w. Close must be
+// called once all the content has been written.
+func NewWriter(def *editorconfig.Definition, w io.Writer) *Writer {
+	return &Writer{
+		def:      def,
+		w:        w,
+		tabWidth: tabWidth(def),
+		eol:      eolSequence(def.EndOfLine),
+	}
+}
+
+// Write rewrites the complete lines of p, keeping the last partial line for
+// later.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	switch w.def.Charset {
+	case editorconfig.CharsetUTF16BE, editorconfig.CharsetUTF16LE:
+		return 0, fmt.Errorf("cannot fix %s content: %w", w.def.Charset, ErrUnsupportedCharset)
+	}
+
+	w.buf = append(w.buf, p...)
+
+	if err := w.flush(false); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close writes the remaining content and the end of the file. It does not
+// close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+
+	if err := w.flush(true); err != nil {
+		return err
+	}
+
+	w.closed = true
+
+	line := w.fixLine(w.buf)
+	w.buf = nil
+
+	insert := w.def.InsertFinalNewline
+
+	// only the last line terminator is removed, not the blank lines before.
+	if len(line) == 0 && insert != nil && !*insert && len(w.held) > 0 {
+		w.held = w.held[:len(w.held)-1]
+	}
+
+	end := w.flushHeld(line)
+
+	if len(line) > 0 && insert != nil && *insert {
+		end = append(end, w.lineEnding(nil)...)
+	}
+
+	return w.write(end)
+}
+
+// flush handles the BOM and writes out the complete lines.
+func (w *Writer) flush(final bool) error {
+	if !w.started {
+		if !final && len(w.buf) < len(bomUTF8) && bytes.HasPrefix(bomUTF8, w.buf) {
+			return nil
+		}
+
+		w.started = true
+		w.hadBOM = bytes.HasPrefix(w.buf, bomUTF8)
+		w.buf = bytes.TrimPrefix(w.buf, bomUTF8)
+
+		if w.writeBOM() {
+			if err := w.write(bomUTF8); err != nil {
+				return err
+			}
+		}
+	}
+
+	var out []byte
+
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+
+		// a CR at the end might be followed by a LF.
+		if !final && w.buf[i] == '\r' && i+1 == len(w.buf) {
+			break
+		}
+
+		size := 1
+		if w.buf[i] == '\r' && i+1 < len(w.buf) && w.buf[i+1] == '\n' {
+			size = 2
+		}
+
+		line := w.fixLine(w.buf[:i])
+		terminator := w.lineEnding(w.buf[i : i+size])
+
+		if len(line) > 0 {
+			out = append(out, w.flushHeld(line)...)
+		}
+
+		w.held = append(w.held, terminator)
+		w.buf = w.buf[i+size:]
+	}
+
+	return w.write(out)
+}
+
+// flushHeld returns the held line terminators followed by the line.
+func (w *Writer) flushHeld(line []byte) []byte {
+	var out []byte
+
+	for _, terminator := range w.held {
+		out = append(out, terminator...)
+	}
+
+	w.held = w.held[:0]
+
+	return append(out, line...)
+}
+
+func (w *Writer) write(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	if _, err := w.w.Write(p); err != nil {
+		return fmt.Errorf("cannot write fixed content: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Writer) writeBOM() bool {
+	switch w.def.Charset {
+	case editorconfig.CharsetUTF8BOM:
+		return true
+	case editorconfig.CharsetUTF8, editorconfig.CharsetLatin1:
+		return false
+	default:
+		return w.hadBOM
+	}
+}
+
+// lineEnding returns the line terminator to use instead of the given one.
+// The first terminator seen is used when end_of_line is not set.
+func (w *Writer) lineEnding(terminator []byte) []byte {
+	if w.eol == nil {
+		if terminator == nil {
+			return []byte("\n")
+		}
+
+		w.eol = append([]byte(nil), terminator...)
+
+		return terminator
+	}
+
+	if terminator == nil || w.def.EndOfLine != "" {
+		return w.eol
+	}
+
+	return terminator
+}
+
+// fixLine rewrites the indentation and trailing whitespace of the line.
+func (w *Writer) fixLine(line []byte) []byte {
+	if w.def.TrimTrailingWhitespace != nil && *w.def.TrimTrailingWhitespace {
+		line = bytes.TrimRight(line, " \t")
+	}
+
+	content := bytes.TrimLeft(line, " \t")
+	indent := line[:len(line)-len(content)]
+
+	out := make([]byte, 0, len(line))
+	out = append(out, w.fixIndentation(indent)...)
+
+	return append(out, content...)
+}
+
+func (w *Writer) fixIndentation(indent []byte) []byte {
+	width := 0
+
+	for _, c := range indent {
+		if c == '\t' {
+			width += w.tabWidth - width%w.tabWidth
+		} else {
+			width++
+		}
+	}
+
+	switch w.def.IndentStyle {
+	case editorconfig.IndentStyleTab:
+		return []byte(strings.Repeat("\t", width/w.tabWidth) + strings.Repeat(" ", width%w.tabWidth))
+	case editorconfig.IndentStyleSpaces:
+		return []byte(strings.Repeat(" ", width))
+	default:
+		return indent
+	}
+}
+
+// Reader reads content from an underlying reader and rewrites it.
+type Reader struct {
+	r   io.Reader
+	w   *Writer
+	out bytes.Buffer
+	buf []byte
+	eof bool
+}
+
+// NewReader returns a Reader rewriting the content read from r.
+func NewReader(def *editorconfig.Definition, r io.Reader) *Reader {
+	reader := &Reader{
+		r:   r,
+		buf: make([]byte, 32*1024),
+	}
+
+	reader.w = NewWriter(def, &reader.out)
+
+	return reader
+}
+
+// Read reads the rewritten content.
+func (r *Reader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 && !r.eof {
+		n, err := r.r.Read(r.buf)
+		if n > 0 {
+			if _, werr := r.w.Write(r.buf[:n]); werr != nil {
+				return 0, werr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			r.eof = true
+
+			if err := r.w.Close(); err != nil {
+				return 0, err
+			}
+		} else if err != nil {
+			return 0, fmt.Errorf("cannot read content: %w", err)
+		}
+	}
+
+	if r.out.Len() == 0 {
+		return 0, io.EOF
+	}
+
+	return r.out.Read(p) //nolint:wrapcheck
+}
+
+func eolSequence(endOfLine string) []byte {
+	switch endOfLine {
+	case editorconfig.EndOfLineLf:
+		return []byte("\n")
+	case editorconfig.EndOfLineCr:
+		return []byte("\r")
+	case editorconfig.EndOfLineCrLf:
+		return []byte("\r\n")
+	default:
+		return nil
+	}
+}
+
+// tabWidth returns the width of a tab, as defined by tab_width or indent_size.
+func tabWidth(def *editorconfig.Definition) int {
+	if def.TabWidth > 0 {
+		return def.TabWidth
+	}
+
+	if size, err := strconv.Atoi(def.IndentSize); err == nil && size > 0 {
+		return size
+	}
+
+	return defaultTabWidth
+}
@@ -0,0 +1,134 @@
+package fixer //nolint:testpackage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestFix(t *testing.T) { //nolint:funlen
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		def      editorconfig.Definition
+		content  string
+		expected string
+	}{
+		{
+			name:     "untouched",
+			content:  "a \r\n\tb\rc",
+			expected: "a \r\n\tb\rc",
+		},
+		{
+			name:     "end of line",
+			def:      editorconfig.Definition{EndOfLine: editorconfig.EndOfLineCrLf},
+			content:  "a\nb\r\nc\rd",
+			expected: "a\r\nb\r\nc\r\nd",
+		},
+		{
+			name:     "trailing whitespace",
+			def:      editorconfig.Definition{TrimTrailingWhitespace: boolPtr(true)},
+			content:  "a \t\n \nb ",
+			expected: "a\n\nb",
+		},
+		{
+			name:     "insert final newline",
+			def:      editorconfig.Definition{InsertFinalNewline: boolPtr(true)},
+			content:  "a\r\nb",
+			expected: "a\r\nb\r\n",
+		},
+		{
+			name:     "insert final newline on empty content",
+			def:      editorconfig.Definition{InsertFinalNewline: boolPtr(true)},
+			content:  "",
+			expected: "",
+		},
+		{
+			name:     "remove final newline",
+			def:      editorconfig.Definition{InsertFinalNewline: boolPtr(false)},
+			content:  "a\n\nb\r\n",
+			expected: "a\n\nb",
+		},
+		{
+			name:     "remove final newline only",
+			def:      editorconfig.Definition{InsertFinalNewline: boolPtr(false)},
+			content:  "a\n\nb\n\n\n",
+			expected: "a\n\nb\n\n",
+		},
+		{
+			name: "trailing blank line with final newline",
+			def: editorconfig.Definition{
+				InsertFinalNewline:     boolPtr(true),
+				TrimTrailingWhitespace: boolPtr(true),
+			},
+			content:  "a\n  ",
+			expected: "a\n",
+		},
+		{
+			name:     "spaces to tabs",
+			def:      editorconfig.Definition{IndentStyle: editorconfig.IndentStyleTab, TabWidth: 4},
+			content:  "    a\n      b\n  \tc\n",
+			expected: "\ta\n\t  b\n\tc\n",
+		},
+		{
+			name:     "tabs to spaces",
+			def:      editorconfig.Definition{IndentStyle: editorconfig.IndentStyleSpaces, IndentSize: "2"},
+			content:  "\ta\n\t\tb\n \tc\t\n",
+			expected: "  a\n    b\n  c\t\n",
+		},
+		{
+			name:     "add bom",
+			def:      editorconfig.Definition{Charset: editorconfig.CharsetUTF8BOM},
+			content:  "a\n",
+			expected: "\xef\xbb\xbfa\n",
+		},
+		{
+			name:     "remove bom",
+			def:      editorconfig.Definition{Charset: editorconfig.CharsetUTF8},
+			content:  "\xef\xbb\xbfa\n",
+			expected: "a\n",
+		},
+		{
+			name:     "keep bom",
+			content:  "\xef\xbb\xbfa\n",
+			expected: "\xef\xbb\xbfa\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.NewBuffer(nil)
+
+			err := Fix(&test.def, buf, strings.NewReader(test.content))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, buf.String())
+
+			// byte by byte, to split the CRLF and BOM.
+			data, err := io.ReadAll(NewReader(&test.def, iotest.OneByteReader(strings.NewReader(test.content))))
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
+
+func TestFixUTF16(t *testing.T) {
+	t.Parallel()
+
+	def := &editorconfig.Definition{Charset: editorconfig.CharsetUTF16LE}
+
+	err := Fix(def, io.Discard, strings.NewReader("\xff\xfea\x00"))
+	assert.Equal(t, true, errors.Is(err, ErrUnsupportedCharset))
+}
@@ -0,0 +1,295 @@
+package editorconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/syntax"
+)
+
+// FlattenDifference is a file whose definition changes with the flattened
+// configuration file.
+type FlattenDifference struct {
+	Filename string
+	// Nested and Flattened are the definitions given by the nested
+	// configuration files and by the flattened one.
+	Nested    *Definition
+	Flattened *Definition
+}
+
+// flattenLevel is a configuration file, in dir relative to the flattened
+// one, or above it when up is set.
+type flattenLevel struct {
+	dir string
+	up  bool
+	ec  *Editorconfig
+}
+
+// Flatten merges the configuration files of the tree rooted at dir, and the
+// ones above it up to the root one, into a single root configuration file
+// for dir. The selectors are anchored to the directory of their file, the
+// closest files coming last to keep their precedence.
+//
+// A nested root configuration file is preceded by a section unsetting, for
+// the files below it, the properties of the sections before it.
+//
+// The sections above dir whose selector cannot be anchored to dir, e.g.
+// "/{src,lib}/*.go", are left out. The definitions of the files of the tree
+// are therefore verified, the files given a different definition by the
+// flattened file being returned.
+func (config *Config) Flatten(dir string) (*syntax.File, []FlattenDifference, error) {
+	absDir, parse, err := config.resolve(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	levels, err := config.parentLevels(absDir, parse)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nested := make(map[string]*Definition)
+
+	err = config.WalkDefinitions(dir, func(name string, d fs.DirEntry, def *Definition, err error) error {
+		if err != nil && !isWarning(err) {
+			return err
+		}
+
+		if d == nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return fmt.Errorf("cannot get relative path of %q: %w", name, err)
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if !d.IsDir() {
+			nested[name] = def
+
+			return nil
+		}
+
+		if d.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		ec, _, err := parse(filepath.Join(absDir, filepath.FromSlash(rel), config.ConfigName()))
+		if err == nil {
+			levels = append(levels, flattenLevel{dir: rel, ec: ec})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot walk %q: %w", dir, err)
+	}
+
+	f := flatten(levels)
+
+	differences, err := config.verifyFlatten(f, dir, absDir, nested)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return f, differences, nil
+}
+
+// parentLevels returns the configuration file of the directory and the ones
+// above it up to the root one, the farthest first.
+func (config *Config) parentLevels(absDir string, parse func(string) (*Editorconfig, error, error)) ([]flattenLevel, error) {
+	var levels []flattenLevel
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		ec, _, err := parse(filepath.Join(current, config.ConfigName()))
+
+		switch {
+		case err == nil:
+			rel, err := filepath.Rel(current, absDir)
+			if err != nil {
+				return nil, fmt.Errorf("cannot get relative path of %q: %w", absDir, err)
+			}
+
+			levels = append([]flattenLevel{{dir: filepath.ToSlash(rel), up: current != absDir, ec: ec}}, levels...)
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("cannot parse the ini file %q: %w", config.ConfigName(), err)
+		}
+
+		if err == nil && ec.Root || current == filepath.Dir(current) {
+			return levels, nil
+		}
+	}
+}
+
+// flatten writes the sections of the levels into a root file.
+func flatten(levels []flattenLevel) *syntax.File {
+	f, _ := syntax.Parse(bytes.NewReader(nil))
+
+	f.Preamble.Lines = append(f.Preamble.Lines, syntax.NewComment("# https://editorconfig.org"), syntax.NewBlank())
+	f.SetRoot(true)
+
+	// keys are the properties set by the sections so far.
+	keys := make(map[string]bool)
+
+	for _, level := range levels {
+		if level.ec.Root && !level.up && level.dir != "." && len(keys) > 0 {
+			selector := "/" + escapeGlob(level.dir) + "/**"
+
+			f.AddSection(selector)
+
+			for _, key := range slices.Sorted(maps.Keys(keys)) {
+				f.Set(selector, key, UnsetValue)
+			}
+		}
+
+		for _, def := range level.ec.Definitions {
+			selector, ok := level.anchor(def.Selector)
+			if !ok || len(def.Raw) == 0 {
+				continue
+			}
+
+			f.AddSection(selector)
+
+			for _, key := range def.keys() {
+				f.Set(selector, key, def.Raw[key])
+				keys[key] = true
+			}
+		}
+	}
+
+	return f
+}
+
+// anchor returns the selector, relative to the directory of the level,
+// anchored to the flattened file. It returns false for a level above it
+// whose selector cannot be anchored.
+func (level flattenLevel) anchor(selector string) (string, bool) {
+	anchored := anchorSelector(selector)
+
+	switch {
+	case level.dir == ".":
+		return anchored, true
+	case !level.up:
+		return "/" + escapeGlob(level.dir) + anchored, true
+	case strings.HasPrefix(anchored, "/**/"):
+		// the names without a slash match at any depth.
+		return anchored, true
+	}
+
+	prefix := "/" + escapeGlob(level.dir) + "/"
+	if rest, ok := strings.CutPrefix(anchored, prefix); ok {
+		return "/" + rest, true
+	}
+
+	return "", false
+}
+
+// escapeGlob escapes the special characters of the globs.
+func escapeGlob(name string) string {
+	var b strings.Builder
+
+	for _, r := range name {
+		if strings.ContainsRune(`\*?[]{},!`, r) {
+			b.WriteRune('\\')
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// verifyFlatten compares the definitions given by the flattened file, as if
+// it was in dir, with the nested ones of the files.
+func (config *Config) verifyFlatten(f *syntax.File, dir string, absDir string, nested map[string]*Definition) ([]FlattenDifference, error) {
+	ec, _ := newEditorconfig(f, path.Join(dir, config.ConfigName()))
+
+	names := make([]string, 0, len(nested))
+	for name := range nested {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var differences []FlattenDifference
+
+	for _, name := range names {
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get relative path of %q: %w", name, err)
+		}
+
+		flattened, err := config.newDefinition()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := config.merge(flattened, ec, absDir, filepath.Join(absDir, rel)); err != nil {
+			return nil, err
+		}
+
+		if !equalDefinitions(effective(nested[name]), effective(flattened)) {
+			differences = append(differences, FlattenDifference{
+				Filename:  name,
+				Nested:    nested[name],
+				Flattened: flattened,
+			})
+		}
+	}
+
+	return differences, nil
+}
+
+// effective returns the definition without the unset properties, which are
+// the same as the properties not set.
+func effective(d *Definition) *Definition {
+	e := &Definition{
+		Raw:     make(map[string]string, len(d.Raw)),
+		version: d.version,
+	}
+
+	for key, value := range d.Raw {
+		if value != UnsetValue {
+			e.Raw[key] = value
+		}
+	}
+
+	e.Charset = e.Raw["charset"]
+	e.IndentSize = e.Raw["indent_size"]
+	_ = e.normalize()
+
+	return e
+}
+
+// isWarning tells whether the error only wraps warnings.
+func isWarning(err error) bool {
+	switch e := err.(type) { //nolint:errorlint
+	case *ParseError:
+		return e.Severity == SeverityWarning
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if !isWarning(err) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+// flattenFiles are the files of a project with sections to rewrite when flattening.
+var flattenFiles = map[string]string{
+	".editorconfig": "root = true\n[*]\nindent_style = space\n[*.go]\nindent_style = tab\n" +
+		"[src/lib/*]\ncharset = latin1\n[{src,doc}/*.md]\nindent_size = 2\n",
+	"main.go":                "",
+	"README.md":              "",
+	"src/.editorconfig":      "[*.go]\nindent_size = 4\n[lib/*]\nend_of_line = lf\n[]\n",
+	"src/main.go":            "",
+	"src/lib/a.go":           "",
+	"src/lib/b.txt":          "",
+	"src/a[1]/.editorconfig": "[*]\ninsert_final_newline = true\n",
+	"src/a[1]/c.go":          "",
+}
+
+func TestFlatten(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(flattenFiles)}
+
+	f, differences, err := config.Flatten(".")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(differences))
+	assert.Equal(t, "# https://editorconfig.org\nroot = true\n\n"+
+		"[/**/*]\nindent_style = space\n\n"+
+		"[/**/*.go]\nindent_style = tab\n\n"+
+		"[/src/lib/*]\ncharset = latin1\n\n"+
+		"[/{src,doc}/*.md]\nindent_size = 2\n\n"+
+		"[/src/**/*.go]\nindent_size = 4\n\n"+
+		"[/src/lib/*]\nend_of_line = lf\n\n"+
+		"[/src/a\\[1\\]/**/*]\ninsert_final_newline = true\n", string(f.Bytes()))
+}
+
+func TestFlattenBelowParents(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFS(flattenFiles)
+	fsys["src/README.md"] = &fstest.MapFile{}
+
+	config := &Config{FS: fsys}
+
+	f, differences, err := config.Flatten("src")
+	assert.Nil(t, err)
+	assert.Equal(t, "# https://editorconfig.org\nroot = true\n\n"+
+		"[/**/*]\nindent_style = space\n\n"+
+		"[/**/*.go]\nindent_style = tab\n\n"+
+		"[/lib/*]\ncharset = latin1\n\n"+
+		"[/**/*.go]\nindent_size = 4\n\n"+
+		"[/lib/*]\nend_of_line = lf\n\n"+
+		"[/a\\[1\\]/**/*]\ninsert_final_newline = true\n", string(f.Bytes()))
+
+	// [{src,doc}/*.md] cannot be anchored to src.
+	assert.Equal(t, 1, len(differences))
+	assert.Equal(t, "src/README.md", differences[0].Filename)
+	assert.Equal(t, "2", differences[0].Nested.IndentSize)
+	assert.Equal(t, "", differences[0].Flattened.IndentSize)
+}
+
+func TestFlattenNestedRoot(t *testing.T) {
+	t.Parallel()
+
+	fsys := mapFS(flattenFiles)
+	fsys["vendor/.editorconfig"] = &fstest.MapFile{Data: []byte("root = true\n[*]\nindent_size = 8\n")}
+	fsys["vendor/lib.go"] = &fstest.MapFile{}
+
+	config := &Config{FS: fsys}
+
+	f, differences, err := config.Flatten(".")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(differences))
+	assert.Equal(t, "# https://editorconfig.org\nroot = true\n\n"+
+		"[/**/*]\nindent_style = space\n\n"+
+		"[/**/*.go]\nindent_style = tab\n\n"+
+		"[/src/lib/*]\ncharset = latin1\n\n"+
+		"[/{src,doc}/*.md]\nindent_size = 2\n\n"+
+		"[/src/**/*.go]\nindent_size = 4\n\n"+
+		"[/src/lib/*]\nend_of_line = lf\n\n"+
+		"[/src/a\\[1\\]/**/*]\ninsert_final_newline = true\n\n"+
+		"[/vendor/**]\ncharset = unset\nend_of_line = unset\nindent_size = unset\n"+
+		"indent_style = unset\ninsert_final_newline = unset\n\n"+
+		"[/vendor/**/*]\nindent_size = 8\n", string(f.Bytes()))
+}
@@ -0,0 +1,174 @@
+package editorconfig
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/syntax"
+)
+
+// Format returns the canonical formatting of the content of a .editorconfig
+// file, keeping its comments, its byte order mark and its line terminator.
+//
+//   - the properties are written as lowercase "key = value", the boolean
+//     values being lowercased as well;
+//   - root is moved to the first property of the preamble, and the
+//     properties are sorted by key within the groups of lines separated by
+//     blank lines, the comments preceding a property moving with it, and
+//     root coming first;
+//   - the sections are separated by a single blank line, the blank lines
+//     within a section being collapsed;
+//   - the leading and trailing whitespace is removed, and the file ends with
+//     a newline.
+func Format(src []byte) ([]byte, error) {
+	f, err := syntax.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse: %w", err)
+	}
+
+	var blocks [][]string
+
+	if lines := formatLines(rootFirst(f.Preamble.Lines)); len(lines) > 0 {
+		blocks = append(blocks, lines)
+	}
+
+	for _, s := range f.Sections {
+		blocks = append(blocks, append([]string{"[" + s.Name() + "]"}, formatLines(s.Lines)...))
+	}
+
+	newline := "\n"
+
+	for _, l := range f.Lines() {
+		if l.EOL() != "" {
+			newline = l.EOL()
+
+			break
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+
+	if f.BOM {
+		buf.WriteString("\ufeff")
+	}
+
+	for i, block := range blocks {
+		if i > 0 {
+			buf.WriteString(newline)
+		}
+
+		for _, line := range block {
+			buf.WriteString(line + newline)
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// rootFirst moves the root property, with the comments directly preceding
+// it, before the first property of the preamble.
+func rootFirst(lines []*syntax.Line) []*syntax.Line {
+	first := -1
+
+	for i, l := range lines {
+		if l.Kind() != syntax.Property {
+			continue
+		}
+
+		if strings.EqualFold(l.Key(), "root") {
+			if first < 0 {
+				return lines
+			}
+
+			start := i
+			for start > first && lines[start-1].Kind() == syntax.Comment {
+				start--
+			}
+
+			return slices.Concat(lines[:first], lines[start:i+1], lines[first:start], lines[i+1:])
+		}
+
+		if first < 0 {
+			first = i
+		}
+	}
+
+	return lines
+}
+
+// formatItem is a property, with the comments preceding it.
+type formatItem struct {
+	key   string
+	lines []string
+}
+
+// formatLines formats the lines of a section, sorting the properties within
+// the groups of lines separated by blank lines.
+func formatLines(lines []*syntax.Line) []string {
+	var (
+		result  []string
+		items   []formatItem
+		pending []string
+	)
+
+	flush := func() {
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].key != items[j].key && (items[i].key == "root" || items[j].key != "root" && items[i].key < items[j].key)
+		})
+
+		group := pending
+		for i := len(items) - 1; i >= 0; i-- {
+			group = append(items[i].lines, group...)
+		}
+
+		if len(group) > 0 {
+			if len(result) > 0 {
+				result = append(result, "")
+			}
+
+			result = append(result, group...)
+		}
+
+		items, pending = nil, nil
+	}
+
+	for _, l := range lines {
+		switch l.Kind() {
+		case syntax.Blank:
+			flush()
+		case syntax.Property:
+			key := strings.ToLower(l.Key())
+
+			items = append(items, formatItem{
+				key:   key,
+				lines: append(pending, formatProperty(key, l.Value())),
+			})
+			pending = nil
+		case syntax.Comment, syntax.Invalid, syntax.SectionHeader:
+			pending = append(pending, strings.TrimSpace(l.Text()))
+		}
+	}
+
+	flush()
+
+	return result
+}
+
+// formatProperty writes a property as key = value, lowercasing the boolean
+// values.
+func formatProperty(key string, value string) string {
+	if value == "" {
+		return key + " ="
+	}
+
+	if p, ok := LookupProperty(key); ok && p.Type == TypeBool {
+		if lower := strings.ToLower(value); lower == "true" || lower == "false" {
+			value = lower
+		}
+	}
+
+	return key + " = " + value
+}
@@ -0,0 +1,103 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "empty",
+			src:      "",
+			expected: "",
+		},
+		{
+			name: "spacing and case",
+			src:  "Root=TRUE\n[*.go]\n  Indent_Style=tab  \nINSERT_FINAL_NEWLINE = True\nindent_size =\n",
+			expected: "root = true\n\n[*.go]\nindent_size =\nindent_style = tab\n" +
+				"insert_final_newline = true\n",
+		},
+		{
+			name:     "root first",
+			src:      "# https://editorconfig.org\n\nunknown = 1\n; the top-most file\nroot = true\n",
+			expected: "# https://editorconfig.org\n\n; the top-most file\nroot = true\nunknown = 1\n",
+		},
+		{
+			name:     "root at the start of the preamble",
+			src:      "# https://editorconfig.org\nunknown = 1\n\nb = 2\n# the top-most file\nroot = true\n\n[*]\nroot = false\n",
+			expected: "# https://editorconfig.org\n# the top-most file\nroot = true\nunknown = 1\n\nb = 2\n\n[*]\nroot = false\n",
+		},
+		{
+			name: "groups and comments",
+			src: "[*]\n\n\ntab_width = 8\n# four columns\nindent_size = 4\n\n\n\n" +
+				"charset = utf-8\n# trailing\n\n[Makefile]\nindent_style = tab\n\n\n",
+			expected: "[*]\n# four columns\nindent_size = 4\ntab_width = 8\n\n" +
+				"charset = utf-8\n# trailing\n\n[Makefile]\nindent_style = tab\n",
+		},
+		{
+			name:     "duplicate keys keep their order",
+			src:      "[*]\nindent_size = 2\ncharset = utf-8\nIndent_Size = 4\n",
+			expected: "[*]\ncharset = utf-8\nindent_size = 2\nindent_size = 4\n",
+		},
+		{
+			name:     "line terminators and bom",
+			src:      "\ufeff[*]\r\ncharset=utf-8\r\n[*.md]\r\ntrim_trailing_whitespace=FALSE",
+			expected: "\ufeff[*]\r\ncharset = utf-8\r\n\r\n[*.md]\r\ntrim_trailing_whitespace = false\r\n",
+		},
+		{
+			name:     "enum values are kept",
+			src:      "[*]\nend_of_line = LF\nunknown = TRUE\n",
+			expected: "[*]\nend_of_line = LF\nunknown = TRUE\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted, err := Format([]byte(tt.src))
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, string(formatted))
+
+			again, err := Format(formatted)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, string(again))
+		})
+	}
+}
+
+func TestFormatKeepsDefinitions(t *testing.T) {
+	t.Parallel()
+
+	src, err := os.ReadFile(testFile)
+	assert.Nil(t, err)
+
+	data, err := Format(src)
+	assert.Nil(t, err)
+
+	expected, err := Parse(bytes.NewReader(src))
+	assert.Nil(t, err)
+
+	actual, err := Parse(bytes.NewReader(data))
+	assert.Nil(t, err)
+
+	for _, name := range []string{"main.go", "a.js", "Makefile", "README.md"} {
+		e, err := expected.GetDefinitionForFilename(name)
+		assert.Nil(t, err)
+
+		a, err := actual.GetDefinitionForFilename(name)
+		assert.Nil(t, err)
+
+		assert.Equal(t, true, equalDefinitions(e, a))
+	}
+}
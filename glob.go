@@ -0,0 +1,537 @@
+package editorconfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidGlob is returned when a globbing pattern cannot be compiled.
+var ErrInvalidGlob = errors.New("invalid glob")
+
+type globKind int
+
+const (
+	// globLiteral matches the text.
+	globLiteral globKind = iota
+	// globAny matches any character but the slash, e.g. ?.
+	globAny
+	// globStar matches any string without slashes, e.g. *.
+	globStar
+	// globDoubleStar matches any string, e.g. **.
+	globDoubleStar
+	// globSlashes matches one slash, or two slashes and anything in between,
+	// e.g. /**/.
+	globSlashes
+	// globClass matches a character of the class, e.g. [a-z] or [!ab].
+	globClass
+	// globAlternation matches any of the alternatives, e.g. {a,b}.
+	globAlternation
+	// globRange matches an integer within the range, e.g. {-3..3}.
+	globRange
+	// globJump continues at another instruction of a compiled glob.
+	globJump
+)
+
+type globNode struct {
+	kind globKind
+	text string
+
+	// negated and ranges define a class.
+	negated bool
+	ranges  [][2]rune
+
+	alternatives [][]globNode
+
+	// from and to define a numeric range.
+	from int64
+	to   int64
+}
+
+// globInst is an instruction of a compiled glob, the alternations and the
+// jumps continuing at the targets, and the other nodes at the next
+// instruction.
+type globInst struct {
+	globNode
+
+	targets []int
+}
+
+// Glob is a compiled EditorConfig globbing pattern.
+//
+// It supports *, **, ?, [name], [!name], {s1,s2,s3} with nesting and
+// {num1..num2}, the numeric ranges being checked by parsing the digits.
+//
+// The matching takes a time proportional to the length of the pattern times
+// the square of the length of the name at worst.
+type Glob struct {
+	pattern string
+	program []globInst
+}
+
+// CompileGlob compiles a globbing pattern. The braces and brackets which are
+// not closed are matched literally.
+func CompileGlob(pattern string) (*Glob, error) {
+	nodes, err := parseGlob([]rune(pattern))
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile %q: %w", pattern, err)
+	}
+
+	return &Glob{
+		pattern: pattern,
+		program: compileNodes(nodes, nil),
+	}, nil
+}
+
+// compileNodes appends the instructions of the nodes to the program, the
+// alternatives of an alternation jumping to the instruction following it.
+func compileNodes(nodes []globNode, program []globInst) []globInst {
+	for _, node := range nodes {
+		if node.kind != globAlternation {
+			program = append(program, globInst{globNode: node})
+
+			continue
+		}
+
+		at := len(program)
+		program = append(program, globInst{globNode: globNode{kind: globAlternation}})
+
+		var jumps []int
+
+		for _, alternative := range node.alternatives {
+			program[at].targets = append(program[at].targets, len(program))
+			program = compileNodes(alternative, program)
+
+			jumps = append(jumps, len(program))
+			program = append(program, globInst{globNode: globNode{kind: globJump}})
+		}
+
+		for _, jump := range jumps {
+			program[jump].targets = []int{len(program)}
+		}
+	}
+
+	return program
+}
+
+// String returns the source pattern.
+func (g *Glob) String() string {
+	return g.pattern
+}
+
+// Match tells whether the whole name matches the pattern, case included.
+func (g *Glob) Match(name string) bool {
+	m := &globMatcher{
+		program: g.program,
+		name:    name,
+		failed:  make([]bool, (len(g.program)+1)*(len(name)+1)),
+	}
+
+	return m.match(0, 0)
+}
+
+func parseGlob(pat []rune) ([]globNode, error) { //nolint:cyclop,funlen
+	var (
+		nodes   []globNode
+		literal strings.Builder
+	)
+
+	flush := func() {
+		if literal.Len() > 0 {
+			nodes = append(nodes, globNode{kind: globLiteral, text: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	push := func(node globNode) {
+		flush()
+
+		nodes = append(nodes, node)
+	}
+
+	for i := 0; i < len(pat); i++ {
+		switch r := pat[i]; r {
+		case '\\':
+			if i+1 < len(pat) {
+				i++
+				literal.WriteRune(pat[i])
+			}
+		case '?':
+			push(globNode{kind: globAny})
+		case '*':
+			if i+1 < len(pat) && pat[i+1] == '*' {
+				push(globNode{kind: globDoubleStar})
+
+				i++
+			} else {
+				push(globNode{kind: globStar})
+			}
+		case '/':
+			if i+3 < len(pat) && string(pat[i+1:i+4]) == "**/" {
+				push(globNode{kind: globSlashes})
+
+				i += 3
+			} else {
+				literal.WriteRune(r)
+			}
+		case '[':
+			end := closingBracket(pat, i)
+			if end < 0 {
+				literal.WriteRune(r)
+
+				break
+			}
+
+			node, err := parseClass(pat[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+
+			push(node)
+
+			i = end
+		case '{':
+			end := closingBrace(pat, i)
+			if end < 0 {
+				literal.WriteRune(r)
+
+				break
+			}
+
+			node, err := parseBraces(pat[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+
+			if node.kind == globLiteral {
+				// {single} is matched literally, its content being a pattern.
+				inner, err := parseGlob(pat[i+1 : end])
+				if err != nil {
+					return nil, err
+				}
+
+				literal.WriteRune('{')
+				flush()
+
+				nodes = append(nodes, inner...)
+
+				literal.WriteRune('}')
+			} else {
+				push(node)
+			}
+
+			i = end
+		default:
+			literal.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return nodes, nil
+}
+
+// closingBracket returns the position of the bracket closing the class
+// opened at start, or -1 when there is none or the class contains a slash.
+func closingBracket(pat []rune, start int) int {
+	i := start + 1
+	if i < len(pat) && (pat[i] == '!' || pat[i] == '^') {
+		i++
+	}
+
+	// a leading closing bracket is part of the class.
+	if i < len(pat) && pat[i] == ']' {
+		i++
+	}
+
+	for ; i < len(pat); i++ {
+		switch pat[i] {
+		case '\\':
+			i++
+		case '/':
+			return -1
+		case ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// closingBrace returns the position of the brace closing the one at start,
+// or -1 when there is none.
+func closingBrace(pat []rune, start int) int {
+	depth := 0
+
+	for i := start; i < len(pat); i++ {
+		switch pat[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func parseClass(class []rune) (globNode, error) {
+	node := globNode{kind: globClass}
+
+	if len(class) > 0 && (class[0] == '!' || class[0] == '^') {
+		node.negated = true
+		class = class[1:]
+	}
+
+	for i := 0; i < len(class); i++ {
+		from := class[i]
+		if from == '\\' && i+1 < len(class) {
+			i++
+			from = class[i]
+		}
+
+		to := from
+
+		if i+2 < len(class) && class[i+1] == '-' {
+			i += 2
+			to = class[i]
+
+			if to == '\\' && i+1 < len(class) {
+				i++
+				to = class[i]
+			}
+
+			if to < from {
+				return node, fmt.Errorf("range %c-%c out of order: %w", from, to, ErrInvalidGlob)
+			}
+		}
+
+		node.ranges = append(node.ranges, [2]rune{from, to})
+	}
+
+	return node, nil
+}
+
+// parseBraces parses the content of braces, returning a literal node when
+// it is neither an alternation nor a numeric range.
+func parseBraces(content []rune) (globNode, error) {
+	alternatives := splitAlternatives(content)
+
+	if len(alternatives) == 1 {
+		if from, to, ok := parseNumericRange(string(content)); ok {
+			return globNode{kind: globRange, from: min(from, to), to: max(from, to)}, nil
+		}
+
+		return globNode{kind: globLiteral}, nil
+	}
+
+	node := globNode{kind: globAlternation}
+
+	for _, alternative := range alternatives {
+		nodes, err := parseGlob(alternative)
+		if err != nil {
+			return node, err
+		}
+
+		node.alternatives = append(node.alternatives, nodes)
+	}
+
+	return node, nil
+}
+
+// splitAlternatives splits the content at the commas outside of any nested
+// braces.
+func splitAlternatives(content []rune) [][]rune {
+	var alternatives [][]rune
+
+	depth := 0
+	start := 0
+
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alternatives = append(alternatives, content[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(alternatives, content[start:])
+}
+
+// parseNumericRange parses num1..num2.
+func parseNumericRange(s string) (int64, int64, bool) {
+	first, second, ok := strings.Cut(s, "..")
+	if !ok || !isInteger(first) || !isInteger(second) {
+		return 0, 0, false
+	}
+
+	from, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	to, err := strconv.ParseInt(second, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return from, to, true
+}
+
+// isInteger tells whether s is made of digits with an optional sign.
+func isInteger(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	if len(s) == 0 || len(s) > 19 {
+		return false
+	}
+
+	return strings.Trim(s, "0123456789") == ""
+}
+
+// globMatcher runs a program against a name, remembering the failed
+// states so that each of them is explored only once.
+type globMatcher struct {
+	program []globInst
+	name    string
+	failed  []bool
+}
+
+// match tells whether the name, from the offset i, matches the program from
+// the instruction pc.
+func (m *globMatcher) match(pc int, i int) bool {
+	if pc == len(m.program) {
+		return i == len(m.name)
+	}
+
+	state := pc*(len(m.name)+1) + i
+	if m.failed[state] {
+		return false
+	}
+
+	if m.step(pc, i) {
+		return true
+	}
+
+	m.failed[state] = true
+
+	return false
+}
+
+// step matches the instruction pc at the offset i, and the rest of the
+// program after it.
+func (m *globMatcher) step(pc int, i int) bool { //nolint:cyclop
+	inst := &m.program[pc]
+	name := m.name[i:]
+
+	switch inst.kind {
+	case globLiteral:
+		return strings.HasPrefix(name, inst.text) && m.match(pc+1, i+len(inst.text))
+	case globAny:
+		r, size := utf8.DecodeRuneInString(name)
+
+		return size > 0 && r != '/' && m.match(pc+1, i+size)
+	case globStar:
+		// either stop here, or consume one more character.
+		if m.match(pc+1, i) {
+			return true
+		}
+
+		r, size := utf8.DecodeRuneInString(name)
+
+		return size > 0 && r != '/' && m.match(pc, i+size)
+	case globDoubleStar:
+		if m.match(pc+1, i) {
+			return true
+		}
+
+		_, size := utf8.DecodeRuneInString(name)
+
+		return size > 0 && m.match(pc, i+size)
+	case globSlashes:
+		if !strings.HasPrefix(name, "/") {
+			return false
+		}
+
+		for j := 0; j < len(name); j++ {
+			if name[j] == '/' && m.match(pc+1, i+j+1) {
+				return true
+			}
+		}
+
+		return false
+	case globClass:
+		r, size := utf8.DecodeRuneInString(name)
+
+		return size > 0 && inst.contains(r) && m.match(pc+1, i+size)
+	case globAlternation:
+		for _, target := range inst.targets {
+			if m.match(target, i) {
+				return true
+			}
+		}
+
+		return false
+	case globJump:
+		return m.match(inst.targets[0], i)
+	case globRange:
+		return inst.matchRange(name, func(rest string) bool {
+			return m.match(pc+1, len(m.name)-len(rest))
+		})
+	}
+
+	return false
+}
+
+func (node *globNode) contains(r rune) bool {
+	for _, rg := range node.ranges {
+		if rg[0] <= r && r <= rg[1] {
+			return !node.negated
+		}
+	}
+
+	return node.negated
+}
+
+// matchRange matches an integer, written without leading zeros, within the
+// range at the beginning of the name.
+func (node *globNode) matchRange(name string, rest func(string) bool) bool {
+	start := 0
+	if strings.HasPrefix(name, "-") {
+		start = 1
+	}
+
+	end := start
+	for end < len(name) && '0' <= name[end] && name[end] <= '9' {
+		end++
+	}
+
+	for i := end; i > start; i-- {
+		digits := name[:i]
+
+		value, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil || strconv.FormatInt(value, 10) != digits {
+			continue
+		}
+
+		if node.from <= value && value <= node.to && rest(name[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
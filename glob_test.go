@@ -0,0 +1,121 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestGlobMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"a*e.c", "ace.c", true},
+		{"a*e.c", "abcde.c", true},
+		{"a*e.c", "a/e.c", false},
+		{"a**z.c", "a/b/z.c", true},
+		{"d/**/z.c", "d/z.c", true},
+		{"d/**/z.c", "d/a/b/z.c", true},
+		{"d/**/z.c", "dz.c", false},
+		{"som?.c", "some.c", true},
+		{"som?.c", "som/.c", false},
+		{"[\\]ab].g", "].g", true},
+		{"[\\]ab].g", "c.g", false},
+		{"[ab]].g", "b].g", true},
+		{"[ab]].g", "b.g", false},
+		{"[!ab].g", "c.g", true},
+		{"[!ab].g", "a.g", false},
+		{"[a-c]", "b", true},
+		{"[a-c]", "d", false},
+		{"ab[/c", "ab[/c", true},
+		{"[abc", "[abc", true},
+		{"*.{py,js,html}", "main.js", true},
+		{"*.{py,js,html}", "main.go", false},
+		{"{single}.b", "{single}.b", true},
+		{"{single}.b", "single.b", false},
+		{"{}.c", "{}.c", true},
+		{"a{b,c,}.d", "a.d", true},
+		{"{.f", "{.f", true},
+		{"{},b}.h", "{},b}.h", true},
+		{"{{,b,c{d}.i", "{{,b,c{d}.i", true},
+		{"{{,b,c{d}.i", "b.i", false},
+		{"{a\\,b,cd}", "a,b", true},
+		{"{a\\,b,cd}", "cd", true},
+		{"{a\\,b,cd}", "a", false},
+		{"{e,\\},f}", "}", true},
+		{"{e,\\},f}", "f", true},
+		{"{g,\\\\,i}", "\\", true},
+		{"{word,{also},this}.g", "{also}.g", true},
+		{"{a,{b,c}}", "a", true},
+		{"{a,{b,c}}", "c", true},
+		{"{a,{b,c}}", "{b,c}", false},
+		{"{{a,b},c}", "a", true},
+		{"{{a,b},c}", "c", true},
+		{"{{a,b}{c,d},e}", "bd", true},
+		{"{{a,b}{c,d},e}", "ab", false},
+		{"{some,a{*c,b}[ef]}.j", "abcf.j", true},
+		{"{3..120}", "3", true},
+		{"{3..120}", "120", true},
+		{"{3..120}", "121", false},
+		{"{3..120}", "060", false},
+		{"{-5..5}", "-3", true},
+		{"{-5..5}", "-0", false},
+		{"{5..1}", "3", true},
+		{"{1..1000000}", "999999", true},
+		{"file{1..1000000000000}.txt", "file123456789012.txt", true},
+		{"{aardvark..antelope}", "{aardvark..antelope}", true},
+		{"{aardvark..antelope}", "ant", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern+" "+test.name, func(t *testing.T) {
+			t.Parallel()
+
+			g, err := CompileGlob(test.pattern)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, g.Match(test.name))
+		})
+	}
+}
+
+func TestGlobMatchPathological(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+	}{
+		{"*a*a*a*a*a*a*a*a*b", strings.Repeat("a", 40)},
+		{"**a**a**a**a**a**a**a**a**b", strings.Repeat("a/", 40)},
+		{"{a,aa}{a,aa}{a,aa}{a,aa}{a,aa}{a,aa}{a,aa}{a,aa}{a,aa}b", strings.Repeat("a", 40)},
+	}
+
+	for _, test := range tests {
+		g, err := CompileGlob(test.pattern)
+		assert.Nil(t, err)
+
+		start := time.Now()
+
+		assert.Equal(t, false, g.Match(test.name))
+
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("matching %q took %s", test.pattern, elapsed)
+		}
+	}
+}
+
+func TestGlobInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := CompileGlob("[z-a]")
+	if !errors.Is(err, ErrInvalidGlob) {
+		t.Errorf("expected ErrInvalidGlob, got %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+// Package diff computes the unified diff of two texts.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around the changes.
+const context = 3
+
+// Kinds of edits.
+const (
+	opEqual  = ' '
+	opDelete = '-'
+	opInsert = '+'
+)
+
+// edit is a line kept, deleted from the old text or inserted from the new
+// one, old and new being the number of lines of each text before it.
+type edit struct {
+	kind byte
+	old  int
+	new  int
+	line string
+}
+
+// Unified returns the unified diff of the two texts, empty when they are
+// equal. The lines are terminated by either LF, CRLF or CR, a change of line
+// terminator being a change of the line.
+func Unified(oldName string, newName string, oldText []byte, newText []byte) []byte {
+	if bytes.Equal(oldText, newText) {
+		return nil
+	}
+
+	edits := diff(splitLines(oldText), splitLines(newText))
+
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", oldName, newName)
+
+	for _, hunk := range hunks(edits) {
+		writeHunk(buf, hunk)
+	}
+
+	return buf.Bytes()
+}
+
+// splitLines splits the text after each line terminator.
+func splitLines(text []byte) []string {
+	var lines []string
+
+	for len(text) > 0 {
+		i := bytes.IndexAny(text, "\r\n")
+		if i < 0 {
+			i = len(text)
+		} else if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i += 2
+		} else {
+			i++
+		}
+
+		lines = append(lines, string(text[:i]))
+		text = text[i:]
+	}
+
+	return lines
+}
+
+// maxCost is the number of edits looked for from each end of a range before
+// giving up on finding the shortest script, the range being then replaced as
+// a whole. Every line changes e.g. when the line terminators are converted,
+// and the shortest script is then the whole replacement anyway.
+const maxCost = 1024
+
+// diff returns an edit script turning a into b, using the linear space
+// variant of the algorithm of Eugene W. Myers: the middle snake of the
+// shortest script splits the texts into two smaller problems.
+func diff(a []string, b []string) []edit {
+	ids := make(map[string]int)
+	intern := func(lines []string) []int {
+		result := make([]int, len(lines))
+
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+
+			result[i] = id
+		}
+
+		return result
+	}
+
+	s := &script{a: a, b: b, x: intern(a), y: intern(b)}
+	s.compare(0, len(a), 0, len(b))
+
+	return s.edits
+}
+
+// script collects the edits turning the lines a into b, x and y being the
+// lines as integers, equal when the lines are.
+type script struct {
+	a, b  []string
+	x, y  []int
+	edits []edit
+}
+
+// compare appends the edits turning a[aLo:aHi] into b[bLo:bHi].
+func (s *script) compare(aLo int, aHi int, bLo int, bHi int) {
+	for aLo < aHi && bLo < bHi && s.x[aLo] == s.y[bLo] {
+		s.equal(aLo, bLo, 1)
+		aLo++
+		bLo++
+	}
+
+	suffix := 0
+	for aLo < aHi && bLo < bHi && s.x[aHi-1] == s.y[bHi-1] {
+		aHi--
+		bHi--
+		suffix++
+	}
+
+	switch {
+	case aLo == aHi:
+		s.insert(aLo, bLo, bHi)
+	case bLo == bHi:
+		s.delete(aLo, aHi, bLo)
+	default:
+		x, y, u, v, ok := s.middleSnake(aLo, aHi, bLo, bHi)
+		if !ok {
+			s.delete(aLo, aHi, bLo)
+			s.insert(aHi, bLo, bHi)
+
+			break
+		}
+
+		s.compare(aLo, x, bLo, y)
+		s.equal(x, y, u-x)
+		s.compare(u, aHi, v, bHi)
+	}
+
+	s.equal(aHi, bHi, suffix)
+}
+
+// middleSnake returns the diagonal run (x, y) to (u, v) in the middle of the
+// shortest script turning a[aLo:aHi] into b[bLo:bHi], searching from both
+// ends at once. It returns false when the script is longer than maxCost
+// edits from each end.
+func (s *script) middleSnake(aLo int, aHi int, bLo int, bHi int) (int, int, int, int, bool) { //nolint:cyclop,funlen
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+
+	limit := min((n+m+1)/2, maxCost)
+	offset := limit + 1
+
+	// forward[k] is the furthest x reached on the diagonal k = x - y from the
+	// start, backward[k] the furthest from the end on the diagonal k of the
+	// reversed texts.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := forward[offset+k-1] + 1
+			if k == -d || k != d && forward[offset+k-1] < forward[offset+k+1] {
+				x = forward[offset+k+1]
+			}
+
+			x0, y0 := x, x-k
+
+			y := y0
+			for x < n && y < m && s.x[aLo+x] == s.y[bLo+y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			if r := delta - k; odd && r >= -(d-1) && r <= d-1 && x+backward[offset+r] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y, true
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			x := backward[offset+k-1] + 1
+			if k == -d || k != d && backward[offset+k-1] < backward[offset+k+1] {
+				x = backward[offset+k+1]
+			}
+
+			x0, y0 := x, x-k
+
+			y := y0
+			for x < n && y < m && s.x[aHi-1-x] == s.y[bHi-1-y] {
+				x++
+				y++
+			}
+
+			backward[offset+k] = x
+
+			if f := delta - k; !odd && f >= -d && f <= d && x+forward[offset+f] >= n {
+				return aHi - x, bHi - y, aHi - x0, bHi - y0, true
+			}
+		}
+	}
+
+	return 0, 0, 0, 0, false
+}
+
+// equal appends the count lines kept from a[i:] and b[j:].
+func (s *script) equal(i int, j int, count int) {
+	for ; count > 0; count-- {
+		s.edits = append(s.edits, edit{opEqual, i, j, s.a[i]})
+		i++
+		j++
+	}
+}
+
+// delete appends the deletion of a[lo:hi], j lines of b being before.
+func (s *script) delete(lo int, hi int, j int) {
+	for i := lo; i < hi; i++ {
+		s.edits = append(s.edits, edit{opDelete, i, j, s.a[i]})
+	}
+}
+
+// insert appends the insertion of b[lo:hi], i lines of a being before.
+func (s *script) insert(i int, lo int, hi int) {
+	for j := lo; j < hi; j++ {
+		s.edits = append(s.edits, edit{opInsert, i, j, s.b[j]})
+	}
+}
+
+// hunks groups the changes with their surrounding context, the changes
+// separated by less than twice the context being in the same hunk.
+func hunks(edits []edit) [][]edit {
+	var result [][]edit
+
+	start, end := -1, -1
+
+	for i, e := range edits {
+		if e.kind == opEqual {
+			continue
+		}
+
+		if start >= 0 && i-end > 2*context {
+			result = append(result, edits[start:min(end+context, len(edits))])
+			start = -1
+		}
+
+		if start < 0 {
+			start = max(i-context, 0)
+		}
+
+		end = i + 1
+	}
+
+	if start >= 0 {
+		result = append(result, edits[start:min(end+context, len(edits))])
+	}
+
+	return result
+}
+
+func writeHunk(buf *bytes.Buffer, hunk []edit) {
+	oldCount, newCount := 0, 0
+
+	for _, e := range hunk {
+		if e.kind != opInsert {
+			oldCount++
+		}
+
+		if e.kind != opDelete {
+			newCount++
+		}
+	}
+
+	fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(hunk[0].old, oldCount), hunkRange(hunk[0].new, newCount))
+
+	for _, e := range hunk {
+		buf.WriteByte(e.kind)
+		buf.WriteString(e.line)
+
+		switch {
+		case strings.HasSuffix(e.line, "\n"):
+		case strings.HasSuffix(e.line, "\r"):
+			buf.WriteByte('\n')
+		default:
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats the start and the number of lines of a hunk, the start
+// being the line before it when empty.
+func hunkRange(before int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", before+1)
+	}
+
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,195 @@
+package diff //nolint:testpackage
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestUnified(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "change",
+			old:  "a\nb \nc\n",
+			new:  "a\nb\nc\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,3 +1,3 @@\n a\n-b \n+b\n c\n",
+		},
+		{
+			name: "final newline",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name: "line terminators",
+			old:  "a\r\nb\r\n",
+			new:  "a\nb\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,2 +1,2 @@\n-a\r\n-b\r\n+a\n+b\n",
+		},
+		{
+			name: "hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n12\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n" +
+				"@@ -8,5 +9,4 @@\n 8\n 9\n 10\n-11\n 12\n",
+		},
+		{
+			name: "empty",
+			old:  "",
+			new:  "a\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := Unified("old", "new", []byte(test.old), []byte(test.new))
+			assert.Equal(t, test.expected, string(result))
+		})
+	}
+}
+
+func TestUnifiedLarge(t *testing.T) { //nolint:paralleltest
+	var old, changed strings.Builder
+
+	for i := range 20000 {
+		line := strconv.Itoa(i) + "\n"
+
+		old.WriteString(line)
+
+		if i%10 == 0 {
+			line = "changed " + line
+		}
+
+		changed.WriteString(line)
+	}
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+
+	result := Unified("old", "new", []byte(old.String()), []byte(changed.String()))
+
+	runtime.ReadMemStats(&after)
+
+	assert.Equal(t, 2000, strings.Count(string(result), "\n+changed "))
+
+	// a copy of the diagonals at each step would take gigabytes.
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 256<<20 {
+		t.Errorf("allocated %d bytes", allocated)
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a []string, b []string) int {
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	return lengths[0][0]
+}
+
+func TestDiffShortest(t *testing.T) {
+	t.Parallel()
+
+	random := rand.New(rand.NewPCG(1, 2)) //nolint:gosec
+
+	lines := func() []string {
+		result := make([]string, random.IntN(40))
+		for i := range result {
+			result[i] = string(rune('a' + random.IntN(4)))
+		}
+
+		return result
+	}
+
+	for range 500 {
+		a, b := lines(), lines()
+		edits := diff(a, b)
+
+		var old, changed []string
+
+		kept := 0
+
+		for _, e := range edits {
+			if e.kind != opInsert {
+				assert.Equal(t, len(old), e.old)
+				old = append(old, e.line)
+			}
+
+			if e.kind != opDelete {
+				assert.Equal(t, len(changed), e.new)
+				changed = append(changed, e.line)
+			}
+
+			if e.kind == opEqual {
+				kept++
+			}
+		}
+
+		assert.Equal(t, strings.Join(a, ""), strings.Join(old, ""))
+		assert.Equal(t, strings.Join(b, ""), strings.Join(changed, ""))
+		assert.Equal(t, lcs(a, b), kept)
+	}
+}
+
+func TestUnifiedEveryLine(t *testing.T) { //nolint:paralleltest
+	var old, changed, deleted, inserted strings.Builder
+
+	for i := range 10000 {
+		old.WriteString(strconv.Itoa(i) + "\r\n")
+		changed.WriteString(strconv.Itoa(i) + "\n")
+		deleted.WriteString("-" + strconv.Itoa(i) + "\r\n")
+		inserted.WriteString("+" + strconv.Itoa(i) + "\n")
+	}
+
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+
+	result := Unified("old", "new", []byte(old.String()), []byte(changed.String()))
+
+	runtime.ReadMemStats(&after)
+
+	expected := "--- old\n+++ new\n@@ -1,10000 +1,10000 @@\n" + deleted.String() + inserted.String()
+	assert.Equal(t, expected, string(result))
+
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("allocated %d bytes", allocated)
+	}
+}
@@ -0,0 +1,150 @@
+// Package gitignore matches paths against the patterns of .gitignore files.
+package gitignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+)
+
+// Filename is the name of the files holding the patterns.
+const Filename = ".gitignore"
+
+// Matcher holds the patterns of the .gitignore files of a tree, the deepest
+// and last patterns having precedence over the others.
+type Matcher struct {
+	patterns []pattern
+}
+
+type pattern struct {
+	// base is the slash-separated directory the pattern is relative to.
+	base    string
+	glob    *editorconfig.Glob
+	negate  bool
+	dirOnly bool
+}
+
+// Add adds a line of a .gitignore file located in the base directory. The
+// blank lines and the comments are ignored.
+func (m *Matcher) Add(base string, line string) error {
+	line = strings.TrimRight(line, "\r")
+
+	// trailing spaces are ignored unless escaped.
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+
+	if line == "" || line[0] == '#' {
+		return nil
+	}
+
+	p := pattern{
+		base: filepath.ToSlash(filepath.Clean(base)),
+	}
+
+	if line[0] == '!' {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return nil
+	}
+
+	// a pattern without any slash matches at any depth.
+	prefix := "/**/"
+	if strings.Contains(line, "/") {
+		prefix = "/"
+	}
+
+	// the braces are not special in .gitignore files.
+	line = strings.NewReplacer("{", `\{`, "}", `\}`).Replace(strings.TrimPrefix(line, "/"))
+
+	glob, err := editorconfig.CompileGlob(prefix + line)
+	if err != nil {
+		return fmt.Errorf("cannot compile %q: %w", line, err)
+	}
+
+	p.glob = glob
+
+	m.patterns = append(m.patterns, p)
+
+	return nil
+}
+
+// AddFile adds the patterns of the .gitignore file of the directory, if any.
+func (m *Matcher) AddFile(dir string) error {
+	filename := filepath.Join(dir, Filename)
+
+	fp, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("cannot open %q: %w", filename, err)
+	}
+
+	defer fp.Close()
+
+	scanner := bufio.NewScanner(fp)
+
+	for num := 1; scanner.Scan(); num++ {
+		if err := m.Add(dir, scanner.Text()); err != nil {
+			return fmt.Errorf("%s:%d: %w", filename, num, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("cannot read %q: %w", filename, err)
+	}
+
+	return nil
+}
+
+// Match tells whether the path of a file, or of a directory, is ignored.
+func (m *Matcher) Match(path string, isDir bool) bool {
+	path = filepath.ToSlash(filepath.Clean(path))
+	ignored := false
+
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+
+		rel, ok := relative(p.base, path)
+		if !ok {
+			continue
+		}
+
+		if p.glob.Match("/" + rel) {
+			ignored = !p.negate
+		}
+	}
+
+	return ignored
+}
+
+// relative returns the path relative to the base, false when it is not
+// below it.
+func relative(base string, path string) (string, bool) {
+	if base == "." {
+		return path, path != "." && path != ".." && !strings.HasPrefix(path, "../")
+	}
+
+	rel, ok := strings.CutPrefix(path, base+"/")
+
+	return rel, ok && rel != ""
+}
@@ -0,0 +1,76 @@
+package gitignore //nolint:testpackage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	m := new(Matcher)
+
+	for _, line := range []string{
+		"# comment",
+		"",
+		"*.log",
+		"!keep.log",
+		"/build",
+		"tmp/",
+		"docs/**/*.pdf",
+		"{a}.txt",
+		`\#hash`,
+	} {
+		assert.Nil(t, m.Add(".", line))
+	}
+
+	assert.Nil(t, m.Add("sub", "local"))
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"debug.log", false, true},
+		{"deep/dir/debug.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"src/build", true, false},
+		{"tmp", true, true},
+		{"tmp", false, false},
+		{"src/tmp", true, true},
+		{"docs/a.pdf", false, true},
+		{"docs/x/y/a.pdf", false, true},
+		{"a.pdf", false, false},
+		{"{a}.txt", false, true},
+		{"a.txt", false, false},
+		{"#hash", false, true},
+		{"sub/local", false, true},
+		{"local", false, false},
+		{"main.go", false, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, m.Match(test.path, test.isDir))
+	}
+}
+
+func TestAddFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, Filename), []byte("*.o\r\nvendor/\n"), 0o600)
+	assert.Nil(t, err)
+
+	m := new(Matcher)
+	assert.Nil(t, m.AddFile(dir))
+	assert.Nil(t, m.AddFile(filepath.Join(dir, "missing")))
+
+	assert.Equal(t, true, m.Match(filepath.Join(dir, "main.o"), false))
+	assert.Equal(t, true, m.Match(filepath.Join(dir, "vendor"), true))
+	assert.Equal(t, false, m.Match(filepath.Join(dir, "main.c"), false))
+}
@@ -0,0 +1,143 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrHeader is a message without a valid Content-Length header.
+var ErrHeader = errors.New("invalid header")
+
+// conn reads and writes the messages framed by a Content-Length header.
+type conn struct {
+	r *bufio.Reader
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{
+		r: bufio.NewReader(r),
+		w: w,
+	}
+}
+
+// read returns the content of the next message, io.EOF at the end of the
+// stream.
+func (c *conn) read() ([]byte, error) {
+	length := -1
+
+	for {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+
+			return nil, fmt.Errorf("cannot read header: %w", err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%q: %w", line, ErrHeader)
+		}
+
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("%q: %w", line, ErrHeader)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("missing Content-Length: %w", ErrHeader)
+	}
+
+	data := make([]byte, length)
+
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return nil, fmt.Errorf("cannot read content: %w", err)
+	}
+
+	return data, nil
+}
+
+// write sends a message.
+func (c *conn) write(msg *message) error {
+	msg.JSONRPC = "2.0"
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("cannot encode message: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(data), data); err != nil {
+		return fmt.Errorf("cannot write message: %w", err)
+	}
+
+	return nil
+}
+
+// notify sends a notification to the client.
+func (c *conn) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("cannot encode %s: %w", method, err)
+	}
+
+	return c.write(&message{
+		Method: method,
+		Params: data,
+	})
+}
+
+// reply sends the response of a request, either its result or its error.
+// The id is null when the request could not be read.
+func (c *conn) reply(id *json.RawMessage, result any, err error) error {
+	if id == nil {
+		null := json.RawMessage("null")
+		id = &null
+	}
+
+	msg := &message{
+		ID: id,
+	}
+
+	var rerr *responseError
+
+	switch {
+	case errors.As(err, &rerr):
+		msg.Error = rerr
+	case err != nil:
+		msg.Error = &responseError{
+			Code:    codeInvalidRequest,
+			Message: err.Error(),
+		}
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("cannot encode result: %w", err)
+		}
+
+		raw := json.RawMessage(data)
+		msg.Result = &raw
+	}
+
+	return c.write(msg)
+}
@@ -0,0 +1,162 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// document is an opened document, split into lines.
+type document struct {
+	uri   string
+	text  string
+	lines []string
+	// bom tells whether the text starts with a byte order mark, which is
+	// not part of the first line but counts in its positions.
+	bom bool
+}
+
+func newDocument(uri string, text string) *document {
+	d := &document{
+		uri:   uri,
+		text:  text,
+		lines: splitLines(text),
+	}
+
+	d.lines[0], d.bom = strings.CutPrefix(d.lines[0], "\ufeff")
+
+	return d
+}
+
+// splitLines splits the text on LF, CRLF and CR, the text ending with a
+// terminator having an empty last line.
+func splitLines(text string) []string {
+	var lines []string
+
+	for {
+		i := strings.IndexAny(text, "\r\n")
+		if i < 0 {
+			return append(lines, text)
+		}
+
+		lines = append(lines, text[:i])
+
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+
+		text = text[i+1:]
+	}
+}
+
+// line returns the text of the zero-based line, empty when out of range.
+func (d *document) line(line int) string {
+	if line < 0 || line >= len(d.lines) {
+		return ""
+	}
+
+	return d.lines[line]
+}
+
+// position converts a byte offset within the zero-based line.
+func (d *document) position(line int, offset int) Position {
+	text := d.line(line)
+
+	return Position{
+		Line:      line,
+		Character: d.bomLen(line) + utf16Len(text[:min(max(offset, 0), len(text))]),
+	}
+}
+
+// offset converts the position to a byte offset within its line.
+func (d *document) offset(pos Position) int {
+	text := d.line(pos.Line)
+	units := d.bomLen(pos.Line)
+
+	for i, r := range text {
+		if units >= pos.Character {
+			return i
+		}
+
+		units += utf16.RuneLen(r)
+	}
+
+	return len(text)
+}
+
+// bomLen returns the number of UTF-16 code units of the byte order mark
+// preceding the zero-based line.
+func (d *document) bomLen(line int) int {
+	if line == 0 && d.bom {
+		return 1
+	}
+
+	return 0
+}
+
+// end returns the position of the end of the document.
+func (d *document) end() Position {
+	last := len(d.lines) - 1
+
+	return d.position(last, len(d.lines[last]))
+}
+
+// utf16Len returns the number of UTF-16 code units of the string.
+func utf16Len(s string) int {
+	n := 0
+
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+
+		if r == utf8.RuneError {
+			n++
+
+			continue
+		}
+
+		n += utf16.RuneLen(r)
+	}
+
+	return n
+}
+
+// uriToPath returns the path of a file URI, empty for the other schemes.
+// The path of a Windows drive, e.g. file:///C:/dir, starts with its letter.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return ""
+	}
+
+	path := u.Path
+	if len(path) > 2 && path[0] == '/' && isDrive(path[1:]) {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path)
+}
+
+// pathToURI returns the file URI of an absolute path.
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if isDrive(path) {
+		path = "/" + path
+	}
+
+	u := url.URL{
+		Scheme: "file",
+		Path:   path,
+	}
+
+	return u.String()
+}
+
+// isDrive tells whether the slash-separated path starts with a Windows drive
+// letter, e.g. C:/dir.
+func isDrive(path string) bool {
+	return len(path) > 1 && path[1] == ':' &&
+		('a' <= path[0] && path[0] <= 'z' || 'A' <= path[0] && path[0] <= 'Z')
+}
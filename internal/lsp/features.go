@@ -0,0 +1,285 @@
+package lsp
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/editorconfig/editorconfig-core-go/v2"
+	"github.com/editorconfig/editorconfig-core-go/v2/syntax"
+)
+
+// maxDefinitions is the maximum number of files returned by the definition
+// of a section.
+const maxDefinitions = 1000
+
+// parse returns the syntax tree of the document, and the line at the
+// position, nil when the position is past the last line.
+func (d *document) parse(pos Position) (*syntax.File, *syntax.Line) {
+	f, err := syntax.Parse(strings.NewReader(d.text))
+	if err != nil {
+		return &syntax.File{Preamble: &syntax.Section{}}, nil
+	}
+
+	lines := f.Lines()
+	if pos.Line < 0 || pos.Line >= len(lines) {
+		return f, nil
+	}
+
+	return f, lines[pos.Line]
+}
+
+// diagnostics returns the problems of the document, as reported by the
+// parser and the validation against the registered properties.
+func diagnostics(doc *document) []Diagnostic {
+	ec, warning, err := editorconfig.ParseGraceful(strings.NewReader(doc.text))
+	if err != nil {
+		return []Diagnostic{{
+			Severity: DiagnosticError,
+			Source:   source,
+			Message:  err.Error(),
+		}}
+	}
+
+	f, _ := doc.parse(Position{})
+	lines := f.Lines()
+
+	result := make([]Diagnostic, 0)
+
+	for _, pe := range ec.Problems(warning) {
+		severity := DiagnosticWarning
+		if pe.Severity == editorconfig.SeverityError {
+			severity = DiagnosticError
+		}
+
+		var l *syntax.Line
+		if pe.Line > 0 && pe.Line <= len(lines) {
+			l = lines[pe.Line-1]
+		}
+
+		result = append(result, Diagnostic{
+			Range:    problemRange(doc, l, pe),
+			Severity: severity,
+			Source:   source,
+			// the position is given by the range.
+			Message: pe.Err.Error(),
+		})
+	}
+
+	return result
+}
+
+// problemRange returns the range of the key or the value of the problem, or
+// else the rest of its line.
+func problemRange(doc *document, l *syntax.Line, pe *editorconfig.ParseError) Range {
+	if l == nil {
+		return Range{}
+	}
+
+	line := pe.Line - 1
+	start := max(pe.Column-1, 0)
+	end := len(strings.TrimRight(l.Text(), " \t"))
+
+	keyStart, keyEnd := l.KeyRange()
+	valueStart, valueEnd := l.ValueRange()
+
+	switch {
+	case l.Kind() == syntax.Property && start == keyStart:
+		end = keyEnd
+	case l.Kind() == syntax.Property && start == valueStart:
+		end = valueEnd
+	}
+
+	return Range{
+		Start: doc.position(line, start),
+		End:   doc.position(line, max(start, end)),
+	}
+}
+
+// completion proposes the property names, or the values of the property of
+// the line.
+func (s *Server) completion(p *TextDocumentPositionParams) ([]CompletionItem, error) {
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := doc.line(p.Position.Line)[:doc.offset(p.Position)]
+
+	trimmed := strings.TrimSpace(prefix)
+	if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "[") {
+		return nil, nil
+	}
+
+	if key, _, ok := strings.Cut(prefix, "="); ok {
+		return valueCompletions(strings.ToLower(strings.TrimSpace(key))), nil
+	}
+
+	f, _ := doc.parse(p.Position)
+
+	preamble := len(f.Sections) == 0 || f.Sections[0].Header.Num() > p.Position.Line+1
+
+	return keyCompletions(preamble), nil
+}
+
+// keyCompletions proposes the registered properties, root being the only
+// one valid in the preamble.
+func keyCompletions(preamble bool) []CompletionItem {
+	var items []CompletionItem
+
+	for _, p := range editorconfig.Properties() {
+		if strings.HasSuffix(p.Name, "*") || preamble != (p.Name == "root") {
+			continue
+		}
+
+		items = append(items, CompletionItem{
+			Label:         p.Name,
+			Kind:          CompletionProperty,
+			Detail:        p.Type.String(),
+			Documentation: p.Description,
+		})
+	}
+
+	return items
+}
+
+// valueCompletions proposes the values accepted by the property.
+func valueCompletions(key string) []CompletionItem {
+	p, ok := editorconfig.LookupProperty(key)
+	if !ok {
+		return nil
+	}
+
+	var values []string
+
+	if p.Type == editorconfig.TypeBool {
+		values = append(values, "true", "false")
+	}
+
+	values = append(values, p.Values...)
+
+	if key != "root" {
+		values = append(values, editorconfig.UnsetValue)
+	}
+
+	items := make([]CompletionItem, 0, len(values))
+
+	for _, value := range values {
+		items = append(items, CompletionItem{
+			Label: value,
+			Kind:  CompletionValue,
+		})
+	}
+
+	return items
+}
+
+// hover documents the property under the position.
+func (s *Server) hover(p *TextDocumentPositionParams) (*Hover, error) {
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	_, l := doc.parse(p.Position)
+	if l == nil || l.Kind() != syntax.Property {
+		return nil, nil //nolint:nilnil
+	}
+
+	offset := doc.offset(p.Position)
+
+	start, end := l.KeyRange()
+	if offset < start || offset > end {
+		return nil, nil //nolint:nilnil
+	}
+
+	property, ok := editorconfig.LookupProperty(l.Key())
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{
+			Kind:  "markdown",
+			Value: documentation(strings.ToLower(l.Key()), property),
+		},
+		Range: &Range{
+			Start: doc.position(p.Position.Line, start),
+			End:   doc.position(p.Position.Line, end),
+		},
+	}, nil
+}
+
+// documentation describes a property in markdown.
+func documentation(name string, p *editorconfig.Property) string {
+	var b strings.Builder
+
+	b.WriteString("**" + name + "** (" + p.Type.String() + ")\n\n" + p.Description)
+
+	if len(p.Values) > 0 {
+		b.WriteString("\n\nValues: `" + strings.Join(p.Values, "`, `") + "`")
+	}
+
+	if p.Default != "" {
+		b.WriteString("\n\nDefault: `" + p.Default + "`")
+	}
+
+	return b.String()
+}
+
+// definition returns the files matched by the section under the position,
+// the selectors being relative to the directory of the document.
+func (s *Server) definition(p *TextDocumentPositionParams) ([]Location, error) {
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	path := uriToPath(doc.uri)
+
+	_, l := doc.parse(p.Position)
+	if path == "" || l == nil || l.Kind() != syntax.SectionHeader {
+		return nil, nil
+	}
+
+	locations := make([]Location, 0)
+
+	err = new(editorconfig.Config).WalkMatches(filepath.Dir(path), l.Name(), func(name string) error {
+		locations = append(locations, Location{URI: pathToURI(name)})
+
+		if len(locations) >= maxDefinitions {
+			return fs.SkipAll
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+
+	return locations, nil
+}
+
+// formatting replaces the document by its formatted content.
+func (s *Server) formatting(p *DocumentFormattingParams) ([]TextEdit, error) {
+	doc, err := s.document(p.TextDocument.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted, err := editorconfig.Format([]byte(doc.text))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if string(formatted) == doc.text {
+		return []TextEdit{}, nil
+	}
+
+	return []TextEdit{{
+		Range: Range{
+			End: doc.end(),
+		},
+		NewText: string(formatted),
+	}}, nil
+}
@@ -0,0 +1,222 @@
+// Package lsp implements a Language Server Protocol server for the
+// .editorconfig files, over a stream such as the standard input and output.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+)
+
+// ErrNoShutdown is the exit notification received before the shutdown
+// request.
+var ErrNoShutdown = errors.New("exit without shutdown")
+
+// source is the source of the diagnostics.
+const source = "editorconfig"
+
+// textDocumentSyncFull is the synchronization of the documents sending their
+// whole content on each change.
+const textDocumentSyncFull = 1
+
+// Server is a language server, handling the messages one at a time.
+type Server struct {
+	version   string
+	conn      *conn
+	documents map[string]*document
+	shutdown  bool
+}
+
+// NewServer initializes a server, the version being reported to the client.
+func NewServer(version string) *Server {
+	return &Server{
+		version:   version,
+		documents: make(map[string]*document),
+	}
+}
+
+// Serve reads the messages from r and writes the responses and the
+// notifications to w, until the exit notification or the end of r.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+
+	for {
+		data, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		msg := new(message)
+
+		if err := json.Unmarshal(data, msg); err != nil {
+			err = s.conn.reply(nil, nil, &responseError{
+				Code:    codeParseError,
+				Message: err.Error(),
+			})
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return ErrNoShutdown
+			}
+
+			return nil
+		}
+
+		if msg.ID == nil {
+			if err := s.notification(msg.Method, msg.Params); err != nil {
+				log.Printf("%s: %s", msg.Method, err)
+			}
+
+			continue
+		}
+
+		result, err := s.request(msg.Method, msg.Params)
+
+		if err := s.conn.reply(msg.ID, result, err); err != nil {
+			return err
+		}
+	}
+}
+
+// request handles a request, returning its result.
+func (s *Server) request(method string, params json.RawMessage) (any, error) {
+	if s.shutdown {
+		return nil, &responseError{
+			Code:    codeInvalidRequest,
+			Message: "the server is shut down",
+		}
+	}
+
+	switch method {
+	case "initialize":
+		return s.initialize(), nil
+	case "shutdown":
+		s.shutdown = true
+
+		return nil, nil
+	case "textDocument/completion":
+		return handle(params, s.completion)
+	case "textDocument/hover":
+		return handle(params, s.hover)
+	case "textDocument/definition":
+		return handle(params, s.definition)
+	case "textDocument/formatting":
+		return handle(params, s.formatting)
+	}
+
+	return nil, &responseError{
+		Code:    codeMethodNotFound,
+		Message: "method not found: " + method,
+	}
+}
+
+// handle decodes the params of a request and calls fn with them.
+func handle[P any, R any](params json.RawMessage, fn func(*P) (R, error)) (any, error) {
+	p := new(P)
+
+	if err := json.Unmarshal(params, p); err != nil {
+		return nil, &responseError{
+			Code:    codeInvalidParams,
+			Message: err.Error(),
+		}
+	}
+
+	return fn(p)
+}
+
+// notification handles a notification, the unknown ones being ignored.
+func (s *Server) notification(method string, params json.RawMessage) error {
+	switch method {
+	case "textDocument/didOpen":
+		var p DidOpenTextDocumentParams
+
+		if err := json.Unmarshal(params, &p); err != nil {
+			return fmt.Errorf("cannot decode params: %w", err)
+		}
+
+		return s.update(p.TextDocument.URI, p.TextDocument.Text)
+	case "textDocument/didChange":
+		var p DidChangeTextDocumentParams
+
+		if err := json.Unmarshal(params, &p); err != nil {
+			return fmt.Errorf("cannot decode params: %w", err)
+		}
+
+		if len(p.ContentChanges) == 0 {
+			return nil
+		}
+
+		return s.update(p.TextDocument.URI, p.ContentChanges[len(p.ContentChanges)-1].Text)
+	case "textDocument/didClose":
+		var p DidCloseTextDocumentParams
+
+		if err := json.Unmarshal(params, &p); err != nil {
+			return fmt.Errorf("cannot decode params: %w", err)
+		}
+
+		delete(s.documents, p.TextDocument.URI)
+
+		// clears the diagnostics of the closed document.
+		return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+			URI:         p.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	}
+
+	return nil
+}
+
+// initialize returns the capabilities of the server.
+func (s *Server) initialize() any {
+	return map[string]any{
+		"capabilities": map[string]any{
+			"textDocumentSync": textDocumentSyncFull,
+			"completionProvider": map[string]any{
+				"triggerCharacters": []string{"="},
+			},
+			"hoverProvider":              true,
+			"definitionProvider":         true,
+			"documentFormattingProvider": true,
+		},
+		"serverInfo": map[string]any{
+			"name":    source,
+			"version": s.version,
+		},
+	}
+}
+
+// update stores the content of a document and publishes its diagnostics.
+func (s *Server) update(uri string, text string) error {
+	doc := newDocument(uri, text)
+	s.documents[uri] = doc
+
+	return s.conn.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diagnostics(doc),
+	})
+}
+
+// document returns an opened document.
+func (s *Server) document(uri string) (*document, error) {
+	doc, ok := s.documents[uri]
+	if !ok {
+		return nil, &responseError{
+			Code:    codeInvalidParams,
+			Message: "document not opened: " + uri,
+		}
+	}
+
+	return doc, nil
+}
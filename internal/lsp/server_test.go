@@ -0,0 +1,395 @@
+package lsp //nolint:testpackage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+const testURI = "file:///project/.editorconfig"
+
+// frame encodes a request, or a notification when id is 0.
+func frame(t *testing.T, id int, method string, params any) []byte {
+	t.Helper()
+
+	msg := map[string]any{
+		"jsonrpc": "2.0",
+		"method":  method,
+	}
+
+	if id > 0 {
+		msg["id"] = id
+	}
+
+	if params != nil {
+		msg["params"] = params
+	}
+
+	data, err := json.Marshal(msg)
+	assert.Nil(t, err)
+
+	return fmt.Appendf(nil, "Content-Length: %d\r\n\r\n%s", len(data), data)
+}
+
+// serve runs a session, the given frames being followed by the shutdown
+// request and the exit notification. It returns the messages sent by the
+// server.
+func serve(t *testing.T, frames ...[]byte) []*message {
+	t.Helper()
+
+	frames = append(frames, frame(t, 999, "shutdown", nil), frame(t, 0, "exit", nil))
+
+	out := bytes.NewBuffer(nil)
+
+	err := NewServer("test").Serve(bytes.NewReader(bytes.Join(frames, nil)), out)
+	assert.Nil(t, err)
+
+	c := newConn(out, io.Discard)
+
+	var messages []*message
+
+	for {
+		data, err := c.read()
+		if errors.Is(err, io.EOF) {
+			return messages
+		}
+
+		assert.Nil(t, err)
+
+		msg := new(message)
+		assert.Nil(t, json.Unmarshal(data, msg))
+
+		messages = append(messages, msg)
+	}
+}
+
+// result decodes the result of the response to the request id.
+func result(t *testing.T, messages []*message, id int, v any) {
+	t.Helper()
+
+	for _, msg := range messages {
+		if msg.ID != nil && string(*msg.ID) == fmt.Sprint(id) {
+			assert.Equal(t, (*responseError)(nil), msg.Error)
+
+			// a null result is decoded as a nil Result.
+			if msg.Result != nil {
+				assert.Nil(t, json.Unmarshal(*msg.Result, v))
+			}
+
+			return
+		}
+	}
+
+	t.Fatalf("no response to %d", id)
+}
+
+func open(t *testing.T, text string) []byte {
+	t.Helper()
+
+	return frame(t, 0, "textDocument/didOpen", DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: testURI, Text: text},
+	})
+}
+
+func at(line int, character int) TextDocumentPositionParams {
+	return TextDocumentPositionParams{
+		TextDocument: TextDocumentIdentifier{URI: testURI},
+		Position:     Position{Line: line, Character: character},
+	}
+}
+
+func TestServeLifecycle(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t,
+		frame(t, 1, "initialize", map[string]any{}),
+		frame(t, 0, "initialized", map[string]any{}),
+		frame(t, 2, "unknown/method", nil),
+	)
+
+	var initialize struct {
+		Capabilities struct {
+			TextDocumentSync           int  `json:"textDocumentSync"`
+			HoverProvider              bool `json:"hoverProvider"`
+			DefinitionProvider         bool `json:"definitionProvider"`
+			DocumentFormattingProvider bool `json:"documentFormattingProvider"`
+		} `json:"capabilities"`
+	}
+
+	result(t, messages, 1, &initialize)
+	assert.Equal(t, textDocumentSyncFull, initialize.Capabilities.TextDocumentSync)
+	assert.Equal(t, true, initialize.Capabilities.HoverProvider)
+	assert.Equal(t, true, initialize.Capabilities.DefinitionProvider)
+	assert.Equal(t, true, initialize.Capabilities.DocumentFormattingProvider)
+
+	assert.Equal(t, codeMethodNotFound, messages[1].Error.Code)
+	assert.Equal(t, (*responseError)(nil), messages[2].Error)
+}
+
+func TestServeExitWithoutShutdown(t *testing.T) {
+	t.Parallel()
+
+	err := NewServer("test").Serve(bytes.NewReader(frame(t, 0, "exit", nil)), io.Discard)
+	assert.Equal(t, true, errors.Is(err, ErrNoShutdown))
+}
+
+func TestServeParseError(t *testing.T) {
+	t.Parallel()
+
+	out := bytes.NewBuffer(nil)
+	frames := [][]byte{
+		[]byte("Content-Length: 5\r\n\r\n{bad}"),
+		frame(t, 1, "shutdown", nil),
+		frame(t, 0, "exit", nil),
+	}
+
+	err := NewServer("test").Serve(bytes.NewReader(bytes.Join(frames, nil)), out)
+	assert.Nil(t, err)
+
+	data, err := newConn(out, io.Discard).read()
+	assert.Nil(t, err)
+
+	var msg map[string]json.RawMessage
+
+	assert.Nil(t, json.Unmarshal(data, &msg))
+	assert.Equal(t, json.RawMessage("null"), msg["id"])
+	assert.Equal(t, true, msg["error"] != nil)
+}
+
+func TestDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t, open(t, "root = true\n\n[*]\nindent_style = tabs\nindnet_size = 2\nnot a property\nmax_line_length = x\n"))
+
+	var params PublishDiagnosticsParams
+
+	assert.Equal(t, "textDocument/publishDiagnostics", messages[0].Method)
+	assert.Nil(t, json.Unmarshal(messages[0].Params, &params))
+	assert.Equal(t, testURI, params.URI)
+
+	ranges := make([]Range, 0, len(params.Diagnostics))
+	for _, d := range params.Diagnostics {
+		assert.Equal(t, DiagnosticWarning, d.Severity)
+
+		ranges = append(ranges, d.Range)
+	}
+
+	assert.Equal(t, []Range{
+		{Start: Position{Line: 3, Character: 15}, End: Position{Line: 3, Character: 19}},
+		{Start: Position{Line: 4, Character: 0}, End: Position{Line: 4, Character: 11}},
+		{Start: Position{Line: 5, Character: 0}, End: Position{Line: 5, Character: 14}},
+		{Start: Position{Line: 6, Character: 18}, End: Position{Line: 6, Character: 19}},
+	}, ranges)
+}
+
+func TestDiagnosticsBOM(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t, open(t, "\ufeffnot a property\n"))
+
+	var params PublishDiagnosticsParams
+
+	assert.Nil(t, json.Unmarshal(messages[0].Params, &params))
+	assert.Equal(t, 1, len(params.Diagnostics))
+	assert.Equal(t, Range{
+		Start: Position{Line: 0, Character: 1},
+		End:   Position{Line: 0, Character: 15},
+	}, params.Diagnostics[0].Range)
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t,
+		open(t, "r\n[*]\nin\nindent_style = \n# in\n"),
+		frame(t, 1, "textDocument/completion", at(0, 1)),
+		frame(t, 2, "textDocument/completion", at(2, 2)),
+		frame(t, 3, "textDocument/completion", at(3, 15)),
+		frame(t, 4, "textDocument/completion", at(4, 4)),
+	)
+
+	labels := func(id int) []string {
+		var items []CompletionItem
+
+		result(t, messages, id, &items)
+
+		var labels []string
+		for _, item := range items {
+			labels = append(labels, item.Label)
+		}
+
+		return labels
+	}
+
+	assert.Equal(t, []string{"root"}, labels(1))
+	assert.Equal(t, []string{"tab", "space", "unset"}, labels(3))
+	assert.Equal(t, []string(nil), labels(4))
+
+	keys := labels(2)
+	assert.Equal(t, "charset", keys[0])
+	assert.Equal(t, false, contains(keys, "root"))
+	assert.Equal(t, false, contains(keys, "ij_*"))
+	assert.Equal(t, true, contains(keys, "indent_style"))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestHover(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t,
+		open(t, "[*]\n  Indent_Style = tab\n"),
+		frame(t, 1, "textDocument/hover", at(1, 4)),
+		frame(t, 2, "textDocument/hover", at(1, 18)),
+	)
+
+	var hover *Hover
+
+	result(t, messages, 1, &hover)
+	assert.Equal(t, "markdown", hover.Contents.Kind)
+	assert.Equal(t, "**indent_style** (enum)\n\nIndents with hard tabs or soft spaces.\n\nValues: `tab`, `space`", hover.Contents.Value)
+	assert.Equal(t, &Range{Start: Position{Line: 1, Character: 2}, End: Position{Line: 1, Character: 14}}, hover.Range)
+
+	hover = nil
+
+	result(t, messages, 2, &hover)
+	assert.Equal(t, (*Hover)(nil), hover)
+}
+
+func TestDefinition(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"main.go", "src/lib.go", "src/lib.js", ".git/config.go"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+
+		assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		assert.Nil(t, os.WriteFile(path, nil, 0o600))
+	}
+
+	uri := pathToURI(filepath.Join(dir, ".editorconfig"))
+
+	position := func(line int) TextDocumentPositionParams {
+		return TextDocumentPositionParams{
+			TextDocument: TextDocumentIdentifier{URI: uri},
+			Position:     Position{Line: line},
+		}
+	}
+
+	messages := serve(t,
+		frame(t, 0, "textDocument/didOpen", DidOpenTextDocumentParams{
+			TextDocument: TextDocumentItem{URI: uri, Text: "[*.go]\n[src/*]\nindent_style = tab\n"},
+		}),
+		frame(t, 1, "textDocument/definition", position(0)),
+		frame(t, 2, "textDocument/definition", position(1)),
+		frame(t, 3, "textDocument/definition", position(2)),
+	)
+
+	uris := func(id int) []string {
+		var locations []Location
+
+		result(t, messages, id, &locations)
+
+		var uris []string
+		for _, l := range locations {
+			uris = append(uris, l.URI)
+		}
+
+		return uris
+	}
+
+	assert.Equal(t, []string{
+		pathToURI(filepath.Join(dir, "main.go")),
+		pathToURI(filepath.Join(dir, "src", "lib.go")),
+	}, uris(1))
+	assert.Equal(t, []string{
+		pathToURI(filepath.Join(dir, "src", "lib.go")),
+		pathToURI(filepath.Join(dir, "src", "lib.js")),
+	}, uris(2))
+	assert.Equal(t, []string(nil), uris(3))
+}
+
+func TestFormatting(t *testing.T) {
+	t.Parallel()
+
+	messages := serve(t,
+		open(t, "root=true \n\n\t\n[*]\r\n  indent_style=tab  \n; comment  \nindent_size =\n"),
+		frame(t, 1, "textDocument/formatting", DocumentFormattingParams{
+			TextDocument: TextDocumentIdentifier{URI: testURI},
+		}),
+	)
+
+	var edits []TextEdit
+
+	result(t, messages, 1, &edits)
+	assert.Equal(t, []TextEdit{{
+		Range: Range{
+			End: Position{Line: 7, Character: 0},
+		},
+		NewText: "root = true\n\n[*]\n; comment\nindent_size =\nindent_style = tab\n",
+	}}, edits)
+}
+
+func TestDocumentPosition(t *testing.T) {
+	t.Parallel()
+
+	doc := newDocument(testURI, "a\r\n[é𝄞]\rb")
+
+	assert.Equal(t, []string{"a", "[é𝄞]", "b"}, doc.lines)
+	assert.Equal(t, Position{Line: 1, Character: 4}, doc.position(1, 7))
+	assert.Equal(t, 7, doc.offset(Position{Line: 1, Character: 4}))
+	assert.Equal(t, 8, doc.offset(Position{Line: 1, Character: 10}))
+	assert.Equal(t, Position{Line: 2, Character: 1}, doc.end())
+}
+
+func TestDocumentPositionBOM(t *testing.T) {
+	t.Parallel()
+
+	doc := newDocument(testURI, "\ufeffroot = true\nb")
+
+	assert.Equal(t, []string{"root = true", "b"}, doc.lines)
+	assert.Equal(t, Position{Line: 0, Character: 8}, doc.position(0, 7))
+	assert.Equal(t, 7, doc.offset(Position{Line: 0, Character: 8}))
+	assert.Equal(t, 0, doc.offset(Position{Line: 0, Character: 0}))
+	assert.Equal(t, Position{Line: 1, Character: 0}, doc.position(1, 0))
+}
+
+func TestURI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		uri  string
+		path string
+	}{
+		{"file:///project/.editorconfig", "/project/.editorconfig"},
+		{"file:///C:/Users/me/.editorconfig", "C:/Users/me/.editorconfig"},
+		{"file:///d:/a%20b/.editorconfig", "d:/a b/.editorconfig"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.uri, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, filepath.FromSlash(test.path), uriToPath(test.uri))
+			assert.Equal(t, test.uri, pathToURI(filepath.FromSlash(test.path)))
+		})
+	}
+
+	assert.Equal(t, "", uriToPath("untitled:Untitled-1"))
+}
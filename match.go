@@ -0,0 +1,224 @@
+package editorconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Selector is a section name compiled to match the paths relative to the
+// directory of its configuration file, like GetDefinitionForFilename does.
+type Selector struct {
+	name string
+	glob *Glob
+}
+
+// SectionMatches are the files matched by a section of a configuration file.
+type SectionMatches struct {
+	Selector string
+	// Line is the line of the section header.
+	Line  int
+	Files []string
+}
+
+// CompileSelector compiles a section name. A name containing a slash is
+// anchored to the directory of the configuration file, the others match the
+// files at any depth.
+func CompileSelector(name string) (*Selector, error) {
+	glob, err := CompileGlob(anchorSelector(name))
+	if err != nil {
+		return nil, fmt.Errorf("error compiling selector %q: %w", name, err)
+	}
+
+	return &Selector{
+		name: name,
+		glob: glob,
+	}, nil
+}
+
+// Match tells whether the selector matches the slash-separated path,
+// relative to the directory of the configuration file.
+func (s *Selector) Match(filename string) bool {
+	return s.glob.Match(anchorFilename(filename))
+}
+
+// String returns the section name.
+func (s *Selector) String() string {
+	return s.name
+}
+
+// anchorSelector anchors the section name to the directory of its
+// configuration file, see CompileSelector.
+func anchorSelector(selector string) string {
+	if strings.HasPrefix(selector, "/") {
+		return selector
+	}
+
+	if strings.ContainsRune(selector, '/') {
+		return "/" + selector
+	}
+
+	return "/**/" + selector
+}
+
+// anchorFilename makes the relative path start with a slash, as the anchored
+// selectors do.
+func anchorFilename(filename string) string {
+	if strings.HasPrefix(filename, "/") {
+		return filename
+	}
+
+	return "/" + filename
+}
+
+// MatchFiles returns the files below dir matched by the selector, dir being
+// the directory of the configuration file. The directory is read from the FS
+// when set.
+//
+// The .git directories, and the directories having their own root
+// configuration file, are skipped.
+func (config *Config) MatchFiles(dir string, selector string) ([]string, error) {
+	var files []string
+
+	err := config.WalkMatches(dir, selector, func(name string) error {
+		files = append(files, name)
+
+		return nil
+	})
+
+	return files, err
+}
+
+// WalkMatches calls fn for each file below dir matched by the selector, the
+// files being walked like with MatchFiles. The walk stops at the first error
+// returned by fn, fs.SkipAll stopping it without error.
+func (config *Config) WalkMatches(dir string, selector string, fn func(name string) error) error {
+	s, err := CompileSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	return config.walkFiles(dir, func(name string, rel string) error {
+		if s.Match(rel) {
+			return fn(name)
+		}
+
+		return nil
+	})
+}
+
+// Files returns the files below dir, as slash-separated paths relative to
+// it. The files are walked like with MatchFiles.
+func (config *Config) Files(dir string) ([]string, error) {
+	var files []string
+
+	err := config.walkFiles(dir, func(_ string, rel string) error {
+		files = append(files, rel)
+
+		return nil
+	})
+
+	return files, err
+}
+
+// MatchSections parses the configuration file of dir, and returns the files
+// below dir matched by each of its sections, in order. The files are walked
+// like with MatchFiles.
+func (config *Config) MatchSections(dir string) ([]SectionMatches, error) {
+	absDir, parse, err := config.resolve(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ec, _, err := parse(filepath.Join(absDir, config.ConfigName()))
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := make([]*Selector, len(ec.Definitions))
+	matches := make([]SectionMatches, len(ec.Definitions))
+
+	for i, def := range ec.Definitions {
+		selectors[i], err = CompileSelector(def.Selector)
+		if err != nil {
+			return nil, err
+		}
+
+		matches[i] = SectionMatches{
+			Selector: def.Selector,
+			Line:     def.line,
+		}
+	}
+
+	err = config.walkFiles(dir, func(name string, rel string) error {
+		for i, s := range selectors {
+			if s.Match(rel) {
+				matches[i].Files = append(matches[i].Files, name)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// walkFiles calls fn for each file below dir, with its slash-separated path
+// relative to dir, until it returns an error.
+func (config *Config) walkFiles(dir string, fn func(name string, rel string) error) error {
+	absDir, parse, err := config.resolve(dir)
+	if err != nil {
+		return err
+	}
+
+	walk := func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return fmt.Errorf("cannot get relative path of %q: %w", name, err)
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if !d.IsDir() {
+			return fn(name, rel)
+		}
+
+		if name == dir {
+			return nil
+		}
+
+		if d.Name() == ".git" {
+			return fs.SkipDir
+		}
+
+		absName := path.Join(filepath.ToSlash(absDir), rel)
+
+		// the files below a root configuration file are not concerned.
+		if ec, _, err := parse(filepath.FromSlash(path.Join(absName, config.ConfigName()))); err == nil && ec.Root {
+			return fs.SkipDir
+		}
+
+		return nil
+	}
+
+	if config.FS != nil {
+		err = fs.WalkDir(config.FS, dir, walk)
+	} else {
+		err = filepath.WalkDir(dir, walk)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot walk %q: %w", dir, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestSelectorMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		selector string
+		filename string
+		expected bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "src/main.go", true},
+		{"*.go", "/src/main.go", true},
+		{"src/*.go", "src/main.go", true},
+		{"src/*.go", "lib/src/main.go", false},
+		{"/main.go", "main.go", true},
+		{"/main.go", "src/main.go", false},
+		{"{src,lib}/**.{ts,tsx}", "lib/a/b.tsx", true},
+		{"{src,lib}/**.{ts,tsx}", "test/a.ts", false},
+	}
+
+	for _, tt := range tests {
+		s, err := CompileSelector(tt.selector)
+		assert.Nil(t, err)
+		assert.Equal(t, tt.selector, s.String())
+		assert.Equal(t, tt.expected, s.Match(tt.filename))
+	}
+
+	_, err := CompileSelector("[z-a]")
+	assert.Equal(t, true, err != nil)
+}
+
+// matchFiles are the files of a project with a vendored and a nested configuration.
+var matchFiles = map[string]string{
+	".editorconfig":            "root = true\n\n[*]\nend_of_line = lf\n\n[src/*.go]\nindent_style = tab\n\n[*.rb]\nindent_size = 2\n",
+	"main.go":                  "",
+	"src/lib.go":               "",
+	"src/sub/lib.go":           "",
+	".git/config":              "",
+	"vendor/.editorconfig":     "root = true\n",
+	"vendor/src/vendor.go":     "",
+	"nested/.editorconfig":     "[*]\nindent_size = 4\n",
+	"nested/src/not_anchor.go": "",
+}
+
+func TestMatchFiles(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(matchFiles)}
+
+	files, err := config.MatchFiles(".", "*.go")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"main.go", "nested/src/not_anchor.go", "src/lib.go", "src/sub/lib.go"}, files)
+
+	files, err = config.MatchFiles(".", "src/*.go")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"src/lib.go"}, files)
+
+	files, err = config.MatchFiles("src", "*.go")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"src/lib.go", "src/sub/lib.go"}, files)
+}
+
+func TestWalkMatches(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(matchFiles)}
+
+	var files []string
+
+	err := config.WalkMatches(".", "*.go", func(name string) error {
+		files = append(files, name)
+
+		if len(files) == 2 {
+			return fs.SkipAll
+		}
+
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"main.go", "nested/src/not_anchor.go"}, files)
+
+	errStop := errors.New("stop")
+
+	err = config.WalkMatches(".", "*.go", func(string) error {
+		return errStop
+	})
+	assert.Equal(t, true, errors.Is(err, errStop))
+}
+
+func TestMatchSections(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(matchFiles)}
+
+	matches, err := config.MatchSections(".")
+	assert.Nil(t, err)
+	assert.Equal(t, []SectionMatches{
+		{
+			Selector: "*",
+			Line:     3,
+			Files: []string{
+				".editorconfig", "main.go", "nested/.editorconfig", "nested/src/not_anchor.go",
+				"src/lib.go", "src/sub/lib.go",
+			},
+		},
+		{Selector: "src/*.go", Line: 6, Files: []string{"src/lib.go"}},
+		{Selector: "*.rb", Line: 9},
+	}, matches)
+}
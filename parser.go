@@ -1,5 +1,9 @@
 package editorconfig
 
+import (
+	"io/fs"
+)
+
 // Parser interface is responsible for the parsing of the ini file and the
 // globbing patterns.
 type Parser interface {
@@ -16,3 +20,15 @@ type Parser interface {
 	// matches the globbing pattern.
 	FnmatchCase(pattern string, filename string) (bool, error)
 }
+
+// FSParser is a Parser able to read the .editorconfig files from a fs.FS
+// instead of the operating system.
+type FSParser interface {
+	Parser
+
+	// ParseIniGracefulFS takes one .editorconfig (ini format) filename, a
+	// slash-separated path within fsys, and returns its Editorconfig
+	// definition. In case of non fatal warnings, they are in a joined errors
+	// and might be ignored in some cases.
+	ParseIniGracefulFS(fsys fs.FS, filename string) (*Editorconfig, error, error)
+}
@@ -0,0 +1,395 @@
+package editorconfig
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidValue is a property having a value it does not accept.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrDuplicateProperty is a property being registered twice.
+	ErrDuplicateProperty = errors.New("property already registered")
+)
+
+// PropertyType is the type of the values of a property.
+type PropertyType int
+
+// PropertyType possible values.
+const (
+	// TypeString accepts any value.
+	TypeString PropertyType = iota
+	// TypeBool accepts true or false, case insensitive.
+	TypeBool
+	// TypeInt accepts the integers.
+	TypeInt
+	// TypeEnum accepts one of the values of the property, case insensitive.
+	TypeEnum
+	// TypeList accepts a comma-separated list of values.
+	TypeList
+)
+
+// String returns the name of the type.
+func (t PropertyType) String() string {
+	switch t {
+	case TypeBool:
+		return "bool"
+	case TypeInt:
+		return "int"
+	case TypeEnum:
+		return "enum"
+	case TypeList:
+		return "list"
+	case TypeString:
+	}
+
+	return "string"
+}
+
+// Property declares a property and the values it accepts.
+type Property struct {
+	// Name is the lowercase name of the property, a trailing * declares all
+	// the properties sharing the prefix, e.g. ij_*.
+	Name string
+	Type PropertyType
+	// Values are the values of an enum, or the ones accepted beside the
+	// type, e.g. "tab" for indent_size or "off" for max_line_length.
+	Values []string
+	// Default is the value given when the property is not set, if any. The
+	// specification leaves most of the defaults to the editors, e.g. only
+	// root and max_line_length have one among the built-in properties.
+	Default     string
+	Description string
+	// Validate, when set, further checks the values accepted by the type.
+	// Its error is prefixed with the name and the value, e.g. "must be
+	// positive" is reported as "tab_width=-1 must be positive".
+	Validate func(value string) error
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Property)
+)
+
+//nolint:gochecknoinits
+func init() {
+	for _, p := range []Property{
+		{
+			Name:        "root",
+			Type:        TypeBool,
+			Default:     "false",
+			Description: "Stops the search of .editorconfig files, only valid in the preamble.",
+		},
+		{
+			Name:        "indent_style",
+			Type:        TypeEnum,
+			Values:      []string{IndentStyleTab, IndentStyleSpaces},
+			Description: "Indents with hard tabs or soft spaces.",
+		},
+		{
+			Name:        "indent_size",
+			Type:        TypeInt,
+			Values:      []string{IndentStyleTab},
+			Description: "Number of columns of an indentation level, tab uses tab_width.",
+			Validate:    positive,
+		},
+		{
+			Name:        "tab_width",
+			Type:        TypeInt,
+			Description: "Number of columns of a tab character, defaults to indent_size.",
+			Validate:    positive,
+		},
+		{
+			Name:        "end_of_line",
+			Type:        TypeEnum,
+			Values:      []string{EndOfLineLf, EndOfLineCr, EndOfLineCrLf},
+			Description: "Line terminator.",
+		},
+		{
+			Name:        "charset",
+			Type:        TypeEnum,
+			Values:      []string{CharsetLatin1, CharsetUTF8, CharsetUTF8BOM, CharsetUTF16BE, CharsetUTF16LE},
+			Description: "Character set.",
+		},
+		{
+			Name:        "trim_trailing_whitespace",
+			Type:        TypeBool,
+			Description: "Removes the whitespace characters preceding the newlines.",
+		},
+		{
+			Name:        "insert_final_newline",
+			Type:        TypeBool,
+			Description: "Ends the file with a newline.",
+		},
+		{
+			Name:        "max_line_length",
+			Type:        TypeInt,
+			Values:      []string{MaxLineLengthOffValue},
+			Default:     MaxLineLengthOffValue,
+			Description: "Maximum number of columns of a line, off for none.",
+			Validate:    positive,
+		},
+		{
+			Name:        "spelling_language",
+			Type:        TypeString,
+			Description: "Natural language of the file, e.g. en or en-US.",
+			Validate: func(value string) error {
+				if _, err := parseSpellingLanguage(value); err != nil {
+					return fmt.Errorf("is not of the form ss or ss-TT, e.g. en-US: %w", ErrInvalidValue)
+				}
+
+				return nil
+			},
+		},
+		{
+			Name:        "ij_*",
+			Type:        TypeString,
+			Description: "IntelliJ-based IDEs properties.",
+		},
+		{
+			Name:        "csharp_*",
+			Type:        TypeString,
+			Description: "C# code style properties.",
+		},
+		{
+			Name:        "dotnet_*",
+			Type:        TypeString,
+			Description: ".NET code style properties.",
+		},
+	} {
+		if err := RegisterProperty(p); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterProperty declares a property, its name being case insensitive.
+func RegisterProperty(p Property) error {
+	p.Name = strings.ToLower(p.Name)
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[p.Name]; ok {
+		return fmt.Errorf("%s: %w", p.Name, ErrDuplicateProperty)
+	}
+
+	registry[p.Name] = &p
+
+	return nil
+}
+
+// unregisterProperty removes the declaration of a property.
+func unregisterProperty(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	delete(registry, strings.ToLower(name))
+}
+
+// LookupProperty returns the declaration of the property, either by its name
+// or by the longest prefix declaring it.
+func LookupProperty(name string) (*Property, bool) {
+	name = strings.ToLower(name)
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if p, ok := registry[name]; ok {
+		return p, true
+	}
+
+	var found *Property
+
+	for key, p := range registry {
+		prefix, ok := strings.CutSuffix(key, "*")
+		if !ok || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if found == nil || len(p.Name) > len(found.Name) {
+			found = p
+		}
+	}
+
+	return found, found != nil
+}
+
+// Properties returns the declared properties, sorted by name.
+func Properties() []*Property {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	properties := make([]*Property, 0, len(registry))
+	for _, p := range registry {
+		properties = append(properties, p)
+	}
+
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+
+	return properties
+}
+
+// Check verifies that the property accepts the value, unset being always
+// accepted.
+func (p *Property) Check(value string) error {
+	lower := strings.ToLower(value)
+	if lower == UnsetValue {
+		return nil
+	}
+
+	if p.Type == TypeEnum {
+		if !slices.Contains(p.Values, lower) {
+			return p.invalid(value, "one of "+strings.Join(p.Values, ", "))
+		}
+	} else if slices.Contains(p.Values, lower) {
+		return nil
+	}
+
+	switch p.Type {
+	case TypeBool:
+		if lower != "true" && lower != "false" {
+			return p.invalid(value, "true or false")
+		}
+	case TypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return p.invalid(value, "an integer")
+		}
+	case TypeString, TypeEnum, TypeList:
+	}
+
+	if p.Validate != nil {
+		if err := p.Validate(value); err != nil {
+			return fmt.Errorf("%s=%s %w", p.Name, value, err)
+		}
+	}
+
+	return nil
+}
+
+// invalid builds the error of a value not accepted by the property.
+func (p *Property) invalid(value string, expected string) error {
+	if len(p.Values) > 0 && p.Type != TypeEnum {
+		expected += " or " + strings.Join(p.Values, ", ")
+	}
+
+	return fmt.Errorf("%s=%s, expected %s: %w", p.Name, value, expected, ErrInvalidValue)
+}
+
+// positive verifies that an integer is greater than zero.
+func positive(value string) error {
+	if n, err := strconv.Atoi(value); err == nil && n <= 0 {
+		return fmt.Errorf("must be positive: %w", ErrInvalidValue)
+	}
+
+	return nil
+}
+
+// parseSpellingLanguage parses a language of the form ss or ss-TT, ss being
+// an ISO 639 language code and TT an ISO 3166 territory code. It returns the
+// canonical form, e.g. en-US.
+func parseSpellingLanguage(value string) (string, error) {
+	language, territory, hasTerritory := strings.Cut(value, "-")
+
+	if !isLetters(language, 2) || hasTerritory && !isLetters(territory, 2) { //nolint:mnd
+		return "", fmt.Errorf("%s, expected ss or ss-TT, e.g. en-US: %w", value, ErrInvalidValue)
+	}
+
+	if hasTerritory {
+		return strings.ToLower(language) + "-" + strings.ToUpper(territory), nil
+	}
+
+	return strings.ToLower(language), nil
+}
+
+// isLetters tells whether s is made of n ASCII letters.
+func isLetters(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for _, c := range s {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Get returns the value of the property, or its registered default when it
+// is not set or unset.
+func (d *Definition) Get(key string) (string, bool) {
+	key = strings.ToLower(key)
+
+	value, ok := d.Raw[key]
+	if ok && value != UnsetValue {
+		return value, true
+	}
+
+	if p, found := LookupProperty(key); found && p.Default != "" {
+		return p.Default, true
+	}
+
+	return "", false
+}
+
+// Bool returns the value of a boolean property, false when it is not set or
+// invalid.
+func (d *Definition) Bool(key string) (bool, bool) {
+	value, ok := d.Get(key)
+	if !ok {
+		return false, false
+	}
+
+	switch strings.ToLower(value) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+
+	return false, false
+}
+
+// Int returns the value of an integer property, false when it is not set or
+// not an integer, e.g. "off".
+func (d *Definition) Int(key string) (int, bool) {
+	value, ok := d.Get(key)
+	if !ok {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// List returns the comma-separated values of a property, trimmed and
+// without the empty ones.
+func (d *Definition) List(key string) ([]string, bool) {
+	value, ok := d.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	var list []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list, true
+}
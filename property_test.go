@@ -0,0 +1,171 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestPropertyCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		value string
+		valid bool
+	}{
+		{"indent_style", "tab", true},
+		{"indent_style", "Space", true},
+		{"indent_style", "tabs", false},
+		{"indent_size", "tab", true},
+		{"indent_size", "4", true},
+		{"indent_size", "0", false},
+		{"indent_size", "four", false},
+		{"tab_width", "unset", true},
+		{"insert_final_newline", "TRUE", true},
+		{"insert_final_newline", "yes", false},
+		{"max_line_length", "off", true},
+		{"max_line_length", "-1", false},
+		{"ij_any_property", "whatever", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key+"="+test.value, func(t *testing.T) {
+			t.Parallel()
+
+			p, ok := LookupProperty(test.key)
+			assert.Equal(t, true, ok)
+
+			err := p.Check(test.value)
+			assert.Equal(t, test.valid, err == nil)
+
+			if err != nil {
+				assert.Equal(t, true, errors.Is(err, ErrInvalidValue))
+			}
+		})
+	}
+}
+
+func TestPropertyCheckMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key      string
+		value    string
+		expected string
+	}{
+		{"tab_width", "-1", "tab_width=-1 must be positive: invalid value"},
+		{"indent_size", "0", "indent_size=0 must be positive: invalid value"},
+		{"spelling_language", "en_US", "spelling_language=en_US is not of the form ss or ss-TT, e.g. en-US: invalid value"},
+	}
+
+	for _, test := range tests {
+		p, ok := LookupProperty(test.key)
+		assert.Equal(t, true, ok)
+		assert.Equal(t, test.expected, p.Check(test.value).Error())
+	}
+}
+
+// TestRegisterProperty runs alone, as the other tests read the registry.
+func TestRegisterProperty(t *testing.T) { //nolint:paralleltest
+	err := RegisterProperty(Property{
+		Name:    "Test_Registry_Width",
+		Type:    TypeInt,
+		Default: "42",
+	})
+	assert.Nil(t, err)
+
+	t.Cleanup(func() {
+		unregisterProperty("test_registry_width")
+	})
+
+	err = RegisterProperty(Property{Name: "test_registry_width"})
+	assert.Equal(t, true, errors.Is(err, ErrDuplicateProperty))
+
+	p, ok := LookupProperty("TEST_REGISTRY_WIDTH")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, TypeInt, p.Type)
+
+	_, ok = LookupProperty("unknown_property")
+	assert.Equal(t, false, ok)
+
+	def := &Definition{Raw: map[string]string{}}
+
+	n, ok := def.Int("test_registry_width")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 42, n)
+}
+
+func TestBuiltinDefaults(t *testing.T) {
+	t.Parallel()
+
+	def := &Definition{Raw: map[string]string{"root": "unset"}}
+
+	root, ok := def.Bool("root")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, false, root)
+
+	value, ok := def.Get("max_line_length")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, MaxLineLengthOffValue, value)
+
+	_, ok = def.Int("max_line_length")
+	assert.Equal(t, false, ok)
+
+	_, ok = def.Get("indent_style")
+	assert.Equal(t, false, ok)
+}
+
+func TestDefinitionAccessors(t *testing.T) {
+	t.Parallel()
+
+	def := &Definition{Raw: map[string]string{
+		"max_line_length":          "80",
+		"insert_final_newline":     "True",
+		"trim_trailing_whitespace": "unset",
+		"indent_size":              "tab",
+		"dotnet_diagnostic_list":   "a, b,,c",
+	}}
+
+	n, ok := def.Int("Max_Line_Length")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, 80, n)
+
+	_, ok = def.Int("indent_size")
+	assert.Equal(t, false, ok)
+
+	b, ok := def.Bool("insert_final_newline")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, true, b)
+
+	_, ok = def.Bool("trim_trailing_whitespace")
+	assert.Equal(t, false, ok)
+
+	list, ok := def.List("dotnet_diagnostic_list")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, []string{"a", "b", "c"}, list)
+
+	s, ok := def.Get("indent_size")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "tab", s)
+}
+
+func TestParseSpellingLanguage(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]string{
+		"en":    "en",
+		"EN-gb": "en-GB",
+		"de-DE": "de-DE",
+	} {
+		language, err := parseSpellingLanguage(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, language)
+	}
+
+	for _, value := range []string{"", "e", "eng", "en-", "en-USA", "en_US", "e1"} {
+		_, err := parseSpellingLanguage(value)
+		assert.Equal(t, true, errors.Is(err, ErrInvalidValue))
+	}
+}
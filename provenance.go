@@ -0,0 +1,68 @@
+package editorconfig
+
+import (
+	"strings"
+)
+
+// Origin tells where the value of a property comes from.
+type Origin struct {
+	// Filename is the path of the .editorconfig file, empty when parsed from
+	// a reader.
+	Filename string
+	// Selector is the section of the file setting the property.
+	Selector string
+	// Line is the line number of the property, 0 when unknown.
+	Line int
+	// Value is the value as written in the file.
+	Value string
+	// Shadowed are the values overridden by this one, the closest first.
+	Shadowed []*Origin
+}
+
+// Origin returns where the value of the given property comes from, nil when
+// the property is not set.
+func (d *Definition) Origin(key string) *Origin {
+	return d.origins[strings.ToLower(key)]
+}
+
+// origin returns the origin of a property of the definition, which is
+// either a section or a resolved definition.
+func (d *Definition) origin(key string) *Origin {
+	if o, ok := d.origins[key]; ok {
+		return o
+	}
+
+	return &Origin{
+		Filename: d.filename,
+		Selector: d.Selector,
+		Line:     d.lines[key],
+		Value:    d.Raw[key],
+	}
+}
+
+// trackOrigin records the origin of the property coming from the parent
+// definition, or the fact that it is shadowed by the current one.
+func (d *Definition) trackOrigin(key string, md *Definition, inherited bool) {
+	if d.origins == nil {
+		d.origins = make(map[string]*Origin)
+	}
+
+	parent := md.origin(key)
+
+	if inherited {
+		d.origins[key] = parent
+
+		return
+	}
+
+	current, ok := d.origins[key]
+	if !ok {
+		return
+	}
+
+	shadowed := *parent
+	shadowed.Shadowed = nil
+
+	current.Shadowed = append(current.Shadowed, &shadowed)
+	current.Shadowed = append(current.Shadowed, parent.Shadowed...)
+}
@@ -0,0 +1,62 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestOrigin(t *testing.T) {
+	t.Parallel()
+
+	def, err := GetDefinitionForFilename("testdata/root/src/dummy.go")
+	assert.Nil(t, err)
+
+	root, err := filepath.Abs("testdata/root")
+	assert.Nil(t, err)
+
+	assert.Equal(t, &Origin{
+		Filename: filepath.Join(root, "src", ".editorconfig"),
+		Selector: "*.go",
+		Line:     3,
+		Value:    IndentStyleTab,
+		Shadowed: []*Origin{{
+			Filename: filepath.Join(root, ".editorconfig"),
+			Selector: "*.go",
+			Line:     4,
+			Value:    IndentStyleSpaces,
+		}},
+	}, def.Origin("indent_style"))
+
+	assert.Equal(t, &Origin{
+		Filename: filepath.Join(root, ".editorconfig"),
+		Selector: "*.go",
+		Line:     5,
+		Value:    "true",
+	}, def.Origin("Insert_Final_Newline"))
+
+	assert.Equal(t, (*Origin)(nil), def.Origin("charset"))
+}
+
+func TestOriginSections(t *testing.T) {
+	t.Parallel()
+
+	ec, err := Parse(strings.NewReader("[*]\nindent_size = 2\n\n[*.go]\nindent_size = 4\n"))
+	assert.Nil(t, err)
+
+	def, err := ec.GetDefinitionForFilename("main.go")
+	assert.Nil(t, err)
+
+	assert.Equal(t, &Origin{
+		Selector: "*.go",
+		Line:     5,
+		Value:    "4",
+		Shadowed: []*Origin{{
+			Selector: "*",
+			Line:     2,
+			Value:    "2",
+		}},
+	}, def.Origin("indent_size"))
+}
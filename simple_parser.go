@@ -2,15 +2,15 @@ package editorconfig
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-
-	"gopkg.in/ini.v1"
 )
 
 // SimpleParser implements the Parser interface but without doing any caching.
 type SimpleParser struct{}
 
-// ParseIni calls go-ini's Load on the file.
+// ParseIni parses the file.
 func (parser *SimpleParser) ParseIni(filename string) (*Editorconfig, error) {
 	ec, warning, err := parser.ParseIniGraceful(filename)
 	if err != nil {
@@ -20,23 +20,53 @@ func (parser *SimpleParser) ParseIni(filename string) (*Editorconfig, error) {
 	return ec, warning
 }
 
-// ParseIniGraceful calls go-ini's Load on the file and keep warnings in a separate error.
+// ParseIniGraceful parses the file and keep warnings in a separate error.
 func (parser *SimpleParser) ParseIniGraceful(filename string) (*Editorconfig, error, error) {
-	empty := &Editorconfig{}
-
 	fp, err := os.Open(filename)
 	if err != nil {
-		return empty, nil, err //nolint:wrapcheck
+		return &Editorconfig{}, nil, err //nolint:wrapcheck
 	}
 
 	defer fp.Close()
 
-	iniFile, err := ini.Load(fp)
+	return parser.parseIniGraceful(fp, filename)
+}
+
+// ParseIniFS parses the file read from fsys.
+func (parser *SimpleParser) ParseIniFS(fsys fs.FS, filename string) (*Editorconfig, error) {
+	ec, warning, err := parser.ParseIniGracefulFS(fsys, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ec, warning
+}
+
+// ParseIniGracefulFS parses the file read from fsys and keep warnings in a
+// separate error.
+func (parser *SimpleParser) ParseIniGracefulFS(fsys fs.FS, filename string) (*Editorconfig, error, error) {
+	fp, err := fsys.Open(filename)
+	if err != nil {
+		return &Editorconfig{}, nil, err //nolint:wrapcheck
+	}
+
+	defer fp.Close()
+
+	return parser.parseIniGraceful(fp, filename)
+}
+
+func (parser *SimpleParser) parseIniGraceful(fp fs.File, filename string) (*Editorconfig, error, error) {
+	data, err := io.ReadAll(fp)
+	if err != nil {
+		return &Editorconfig{}, nil, fmt.Errorf("cannot read %q: %w", filename, err)
+	}
+
+	ec, warning, err := loadEditorconfig(data, filename)
 	if err != nil {
-		return empty, nil, fmt.Errorf("cannot load %q: %w", filename, err)
+		return ec, nil, fmt.Errorf("cannot load %q: %w", filename, err)
 	}
 
-	return newEditorconfig(iniFile)
+	return ec, warning, nil
 }
 
 // FnmatchCase calls the module's FnmatchCase.
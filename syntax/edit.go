@@ -0,0 +1,183 @@
+package syntax
+
+import (
+	"strings"
+)
+
+// Section returns the last section with the given name, the preamble for an
+// empty name, or nil when not found.
+func (f *File) Section(name string) *Section {
+	if name == "" {
+		return f.Preamble
+	}
+
+	for i := len(f.Sections) - 1; i >= 0; i-- {
+		if f.Sections[i].Name() == name {
+			return f.Sections[i]
+		}
+	}
+
+	return nil
+}
+
+// AddSection appends a new section at the end of the file, separated from
+// the previous one by a blank line.
+func (f *File) AddSection(name string) *Section {
+	last := f.Preamble
+	if len(f.Sections) > 0 {
+		last = f.Sections[len(f.Sections)-1]
+	}
+
+	if last.Header != nil || len(last.Lines) > 0 {
+		if len(last.Lines) == 0 || last.Lines[len(last.Lines)-1].kind != Blank {
+			last.Lines = append(last.Lines, f.newLine(NewBlank()))
+		}
+	}
+
+	s := &Section{
+		Header: f.newLine(NewSectionHeader(name)),
+	}
+
+	f.Sections = append(f.Sections, s)
+
+	return s
+}
+
+// RemoveSection removes all the sections with the given name, and the
+// comments directly above them. It returns false when none were found.
+func (f *File) RemoveSection(name string) bool {
+	found := false
+	sections := f.Sections[:0]
+	previous := f.Preamble
+
+	for _, s := range f.Sections {
+		if s.Name() == name {
+			found = true
+
+			for len(previous.Lines) > 0 && previous.Lines[len(previous.Lines)-1].kind == Comment {
+				previous.Lines = previous.Lines[:len(previous.Lines)-1]
+			}
+
+			continue
+		}
+
+		sections = append(sections, s)
+		previous = s
+	}
+
+	f.Sections = sections
+
+	// Do not leave blank lines at the end of the file.
+	if found {
+		last := f.Preamble
+		if len(f.Sections) > 0 {
+			last = f.Sections[len(f.Sections)-1]
+		}
+
+		for len(last.Lines) > 0 && last.Lines[len(last.Lines)-1].kind == Blank {
+			last.Lines = last.Lines[:len(last.Lines)-1]
+		}
+	}
+
+	return found
+}
+
+// Set sets the value of the key in the last section with the given name,
+// the preamble for an empty name. The section is created when missing.
+//
+// An existing property keeps its position and formatting, a new one is
+// added after the last property of the section, formatted like the other
+// properties of the file.
+func (f *File) Set(section string, key string, value string) {
+	s := f.Section(section)
+	if s == nil {
+		s = f.AddSection(section)
+	}
+
+	if l := s.Property(key); l != nil {
+		l.SetValue(value)
+
+		return
+	}
+
+	indent, separator := f.propertyStyle(s)
+	l := f.newLine(newProperty(indent, key, separator, value, ""))
+
+	// After the last property, or the last non blank line.
+	at := 0
+
+	for i, line := range s.Lines {
+		if line.kind == Property || (s.Header == nil && line.kind != Blank) {
+			at = i + 1
+		}
+	}
+
+	s.Lines = append(s.Lines[:at], append([]*Line{l}, s.Lines[at:]...)...)
+}
+
+// Remove removes the key from all the sections with the given name, the
+// preamble for an empty name. It returns false when the key was not found.
+func (f *File) Remove(section string, key string) bool {
+	found := false
+
+	for _, s := range append([]*Section{f.Preamble}, f.Sections...) {
+		if s.Name() != section {
+			continue
+		}
+
+		lines := s.Lines[:0]
+
+		for _, l := range s.Lines {
+			if l.kind == Property && strings.EqualFold(l.Key(), key) {
+				found = true
+
+				continue
+			}
+
+			lines = append(lines, l)
+		}
+
+		s.Lines = lines
+	}
+
+	return found
+}
+
+// SetRoot sets or removes the root = true property.
+func (f *File) SetRoot(root bool) {
+	if root {
+		f.Set("", "root", "true")
+	} else {
+		f.Remove("", "root")
+	}
+}
+
+// propertyStyle returns the indentation and the separator of the existing
+// properties, preferring the ones of the given section.
+func (f *File) propertyStyle(s *Section) (string, string) {
+	candidates := append([]*Section{s, f.Preamble}, f.Sections...)
+
+	for _, c := range candidates {
+		for _, l := range c.Lines {
+			if l.kind != Property || l.valueStart == len(l.text) {
+				continue
+			}
+
+			indent := l.text[:l.keyStart]
+			if s.Header == nil || c.Header == nil {
+				indent = ""
+			}
+
+			return indent, l.text[l.keyEnd:l.valueStart]
+		}
+	}
+
+	return "", " = "
+}
+
+// newLine sets the line terminator of a new line to the one of the file.
+func (f *File) newLine(l *Line) *Line {
+	l.eol = f.eol()
+
+	return l
+}
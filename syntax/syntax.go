@@ -0,0 +1,412 @@
+// Package syntax implements a lossless concrete syntax tree of the
+// .editorconfig files.
+//
+// Unlike the editorconfig.Editorconfig type, a File keeps the comments, the
+// blank lines, the original casing and the order of the sections and
+// properties, so that it can be edited and written back with a minimal diff.
+package syntax
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Kind is the kind of a Line.
+type Kind int
+
+// Kinds of lines.
+const (
+	// Blank is an empty line, or a line containing only whitespace.
+	Blank Kind = iota
+	// Comment is a line starting with ; or #.
+	Comment
+	// SectionHeader is a line starting with [ and ending with ].
+	SectionHeader
+	// Property is a key = value line.
+	Property
+	// Invalid is any other line, it is ignored by the parsers.
+	Invalid
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Blank:
+		return "blank"
+	case Comment:
+		return "comment"
+	case SectionHeader:
+		return "section header"
+	case Property:
+		return "property"
+	default:
+		return "invalid"
+	}
+}
+
+var bomUTF8 = []byte{0xef, 0xbb, 0xbf}
+
+// Line is a single line of a .editorconfig file.
+type Line struct {
+	kind Kind
+	num  int
+	text string
+	eol  string
+
+	// start and end of the section name, or the property key, in text.
+	keyStart int
+	keyEnd   int
+	// start and end of the property value in text.
+	valueStart int
+	valueEnd   int
+}
+
+// NewProperty creates a property line, using the default formatting.
+func NewProperty(key string, value string) *Line {
+	return newProperty("", key, " = ", value, "\n")
+}
+
+func newProperty(indent string, key string, separator string, value string, eol string) *Line {
+	l := &Line{
+		kind: Property,
+		text: indent + key + separator + value,
+		eol:  eol,
+	}
+
+	l.keyStart = len(indent)
+	l.keyEnd = l.keyStart + len(key)
+	l.valueStart = l.keyEnd + len(separator)
+	l.valueEnd = len(l.text)
+
+	return l
+}
+
+// NewSectionHeader creates a section header line.
+func NewSectionHeader(name string) *Line {
+	return &Line{
+		kind:     SectionHeader,
+		text:     "[" + name + "]",
+		eol:      "\n",
+		keyStart: 1,
+		keyEnd:   1 + len(name),
+	}
+}
+
+// NewComment creates a comment line, text is expected to start with # or ;.
+func NewComment(text string) *Line {
+	return &Line{
+		kind: Comment,
+		text: text,
+		eol:  "\n",
+	}
+}
+
+// NewBlank creates an empty line.
+func NewBlank() *Line {
+	return &Line{
+		kind: Blank,
+		eol:  "\n",
+	}
+}
+
+// parseLine parses a line following the EditorConfig file format.
+func parseLine(num int, text string, eol string) *Line {
+	l := &Line{
+		num:  num,
+		text: text,
+		eol:  eol,
+	}
+
+	trimmed := strings.TrimSpace(text)
+	start := strings.Index(text, trimmed)
+
+	switch {
+	case trimmed == "":
+		l.kind = Blank
+	case trimmed[0] == '#' || trimmed[0] == ';':
+		l.kind = Comment
+	case trimmed[0] == '[' && trimmed[len(trimmed)-1] == ']' && len(trimmed) > 1:
+		l.kind = SectionHeader
+		l.keyStart = start + 1
+		l.keyEnd = start + len(trimmed) - 1
+	default:
+		eq := strings.IndexByte(text, '=')
+		key := strings.TrimSpace(text[:max(eq, 0)])
+
+		if eq < 0 || key == "" {
+			l.kind = Invalid
+
+			break
+		}
+
+		value := strings.TrimSpace(text[eq+1:])
+
+		l.kind = Property
+		l.keyStart = start
+		l.keyEnd = start + len(key)
+		l.valueStart = eq + 1 + strings.Index(text[eq+1:], value)
+		l.valueEnd = l.valueStart + len(value)
+	}
+
+	return l
+}
+
+// Kind returns the kind of the line.
+func (l *Line) Kind() Kind {
+	return l.kind
+}
+
+// Num returns the line number in the parsed file, starting at 1. It is 0 for
+// the lines added afterwards.
+func (l *Line) Num() int {
+	return l.num
+}
+
+// Text returns the content of the line, without its terminator.
+func (l *Line) Text() string {
+	return l.text
+}
+
+// EOL returns the line terminator, "\n", "\r\n", "\r" or "" for the last line
+// of a file without a final newline.
+func (l *Line) EOL() string {
+	return l.eol
+}
+
+// Name returns the name of a section header.
+func (l *Line) Name() string {
+	if l.kind != SectionHeader {
+		return ""
+	}
+
+	return l.text[l.keyStart:l.keyEnd]
+}
+
+// Key returns the key of a property, in its original casing.
+func (l *Line) Key() string {
+	if l.kind != Property {
+		return ""
+	}
+
+	return l.text[l.keyStart:l.keyEnd]
+}
+
+// Value returns the value of a property.
+func (l *Line) Value() string {
+	if l.kind != Property {
+		return ""
+	}
+
+	return l.text[l.valueStart:l.valueEnd]
+}
+
+// KeyRange returns the byte offsets of the property key, or the section
+// name, within the text of the line.
+func (l *Line) KeyRange() (int, int) {
+	return l.keyStart, l.keyEnd
+}
+
+// ValueRange returns the byte offsets of the property value within the text
+// of the line.
+func (l *Line) ValueRange() (int, int) {
+	return l.valueStart, l.valueEnd
+}
+
+// SetValue replaces the value of a property, keeping the rest of the line.
+func (l *Line) SetValue(value string) {
+	if l.kind != Property {
+		return
+	}
+
+	prefix := l.text[:l.valueStart]
+
+	// "key =" becomes "key = value" rather than "key =value".
+	if l.valueStart == len(l.text) && l.keyEnd < l.valueStart-1 && l.text[l.keyEnd] == ' ' {
+		prefix += " "
+	}
+
+	l.text = prefix + value + l.text[l.valueEnd:]
+	l.valueStart = len(prefix)
+	l.valueEnd = l.valueStart + len(value)
+}
+
+// Section is a section header followed by the lines until the next one.
+type Section struct {
+	// Header is the section header, nil for the preamble.
+	Header *Line
+	// Lines are the lines following the header.
+	Lines []*Line
+}
+
+// Name returns the name of the section, empty for the preamble.
+func (s *Section) Name() string {
+	if s.Header == nil {
+		return ""
+	}
+
+	return s.Header.Name()
+}
+
+// Properties returns the property lines of the section.
+func (s *Section) Properties() []*Line {
+	var properties []*Line
+
+	for _, l := range s.Lines {
+		if l.kind == Property {
+			properties = append(properties, l)
+		}
+	}
+
+	return properties
+}
+
+// Property returns the last property line with the given key, nil when not
+// found. Keys are case insensitive.
+func (s *Section) Property(key string) *Line {
+	for i := len(s.Lines) - 1; i >= 0; i-- {
+		if s.Lines[i].kind == Property && strings.EqualFold(s.Lines[i].Key(), key) {
+			return s.Lines[i]
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value of the given key.
+func (s *Section) Get(key string) (string, bool) {
+	l := s.Property(key)
+	if l == nil {
+		return "", false
+	}
+
+	return l.Value(), true
+}
+
+// File is the concrete syntax tree of a .editorconfig file.
+type File struct {
+	// Preamble contains the lines before the first section header, e.g. the
+	// root property.
+	Preamble *Section
+	// Sections are the sections of the file, in order.
+	Sections []*Section
+	// BOM tells whether the file starts with a UTF-8 byte order mark.
+	BOM bool
+
+	// newline is the line terminator of the new lines.
+	newline string
+}
+
+// Parse reads a .editorconfig file.
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read: %w", err)
+	}
+
+	f := &File{
+		Preamble: &Section{},
+		BOM:      bytes.HasPrefix(data, bomUTF8),
+	}
+
+	current := f.Preamble
+	rest := bytes.TrimPrefix(data, bomUTF8)
+
+	for num := 1; len(rest) > 0; num++ {
+		var text, eol []byte
+
+		text, eol, rest = nextLine(rest)
+
+		if f.newline == "" {
+			f.newline = string(eol)
+		}
+
+		l := parseLine(num, string(text), string(eol))
+
+		if l.kind == SectionHeader {
+			current = &Section{Header: l}
+			f.Sections = append(f.Sections, current)
+
+			continue
+		}
+
+		current.Lines = append(current.Lines, l)
+	}
+
+	return f, nil
+}
+
+// nextLine splits the first line from data, a line being terminated by
+// either LF, CRLF or CR.
+func nextLine(data []byte) ([]byte, []byte, []byte) {
+	i := bytes.IndexAny(data, "\r\n")
+	if i < 0 {
+		return data, nil, nil
+	}
+
+	if data[i] == '\r' && i+1 < len(data) && data[i+1] == '\n' {
+		return data[:i], data[i : i+2], data[i+2:]
+	}
+
+	return data[:i], data[i : i+1], data[i+1:]
+}
+
+// Lines returns all the lines of the file, in order.
+func (f *File) Lines() []*Line {
+	lines := append([]*Line(nil), f.Preamble.Lines...)
+
+	for _, s := range f.Sections {
+		lines = append(lines, s.Header)
+		lines = append(lines, s.Lines...)
+	}
+
+	return lines
+}
+
+// WriteTo writes the file to w, the unmodified lines are written back as
+// they were read.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	buf := bytes.NewBuffer(nil)
+
+	if f.BOM {
+		buf.Write(bomUTF8)
+	}
+
+	lines := f.Lines()
+
+	for i, l := range lines {
+		buf.WriteString(l.text)
+
+		eol := l.eol
+		if eol == "" && i < len(lines)-1 {
+			eol = f.eol()
+		}
+
+		buf.WriteString(eol)
+	}
+
+	n, err := w.Write(buf.Bytes())
+	if err != nil {
+		return int64(n), fmt.Errorf("cannot write: %w", err)
+	}
+
+	return int64(n), nil
+}
+
+// Bytes returns the content of the file.
+func (f *File) Bytes() []byte {
+	buf := bytes.NewBuffer(nil)
+
+	f.WriteTo(buf) //nolint:errcheck
+
+	return buf.Bytes()
+}
+
+func (f *File) eol() string {
+	if f.newline == "" {
+		return "\n"
+	}
+
+	return f.newline
+}
@@ -0,0 +1,130 @@
+package syntax //nolint:testpackage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+const testFile = `# https://editorconfig.org/
+root = true
+
+; all the files
+[*]
+  End_Of_Line=LF
+insert_final_newline = true  
+
+# go
+[*.go]
+indent_style = tab
+not a property
+[*.md]
+trim_trailing_whitespace =
+`
+
+func parse(t *testing.T, content string) *File {
+	t.Helper()
+
+	f, err := Parse(strings.NewReader(content))
+	assert.Nil(t, err)
+
+	return f
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	f := parse(t, testFile)
+
+	assert.Equal(t, 3, len(f.Sections))
+	assert.Equal(t, []Kind{Comment, Property, Blank, Comment}, kinds(f.Preamble.Lines))
+
+	root, ok := f.Preamble.Get("ROOT")
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "true", root)
+
+	s := f.Section("*")
+	assert.Equal(t, 5, s.Header.Num())
+	assert.Equal(t, "End_Of_Line", s.Properties()[0].Key())
+	assert.Equal(t, "LF", s.Properties()[0].Value())
+	assert.Equal(t, "true", s.Properties()[1].Value())
+
+	start, end := s.Properties()[0].KeyRange()
+	assert.Equal(t, []int{2, 13}, []int{start, end})
+
+	assert.Equal(t, []Kind{Property, Invalid}, kinds(f.Section("*.go").Lines))
+	assert.Equal(t, "", f.Section("*.md").Lines[0].Value())
+}
+
+func kinds(lines []*Line) []Kind {
+	result := make([]Kind, 0, len(lines))
+
+	for _, l := range lines {
+		result = append(result, l.Kind())
+	}
+
+	return result
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, content := range []string{
+		testFile,
+		"",
+		"\xef\xbb\xbf[*]\r\nindent_size = 2\r\n",
+		"[*]\rindent_size=2",
+		"root=true\n\n\n[*]\n\n",
+	} {
+		assert.Equal(t, content, string(parse(t, content).Bytes()))
+	}
+}
+
+func TestEdit(t *testing.T) {
+	t.Parallel()
+
+	f := parse(t, testFile)
+
+	f.Set("*", "end_of_line", "crlf")
+	f.Set("*", "charset", "utf-8")
+	f.Set("*.md", "trim_trailing_whitespace", "false")
+	f.Remove("*.go", "indent_style")
+	f.RemoveSection("*.go")
+	f.Set("*.{json,yml}", "indent_size", "2")
+
+	expected := `# https://editorconfig.org/
+root = true
+
+; all the files
+[*]
+  End_Of_Line=crlf
+insert_final_newline = true  
+  charset=utf-8
+
+[*.md]
+trim_trailing_whitespace = false
+
+[*.{json,yml}]
+indent_size = 2
+`
+
+	assert.Equal(t, expected, string(f.Bytes()))
+}
+
+func TestEditEmpty(t *testing.T) {
+	t.Parallel()
+
+	f := parse(t, "# comment\r\n")
+
+	f.SetRoot(true)
+	f.Set("*", "indent_style", "space")
+	f.Set("*", "indent_size", "4")
+
+	assert.Equal(t, "# comment\r\nroot = true\r\n\r\n[*]\r\nindent_style = space\r\nindent_size = 4\r\n", string(f.Bytes()))
+
+	f.RemoveSection("*")
+	f.SetRoot(false)
+
+	assert.Equal(t, "# comment\r\n", string(f.Bytes()))
+}
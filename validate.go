@@ -0,0 +1,179 @@
+package editorconfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownProperty is a property which is not registered.
+	ErrUnknownProperty = errors.New("unknown property")
+	// ErrDuplicateKey is a property set more than once in a section.
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrRootInSection is the root property set outside of the preamble.
+	ErrRootInSection = errors.New("root is only valid in the preamble")
+)
+
+// Validate checks the properties of the parsed file against the registry,
+// see RegisterProperty. It reports the invalid values, the unknown keys with
+// a suggestion, and the keys set more than once in a section.
+//
+// The problems are warnings, joined into the returned error. Use
+// ParseErrors to list them.
+func (e *Editorconfig) Validate() error {
+	problems := append([]*ParseError(nil), e.duplicates...)
+
+	for _, def := range e.Definitions {
+		problems = append(problems, def.validate()...)
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	var result error
+
+	for _, pe := range problems {
+		result = errors.Join(result, pe)
+	}
+
+	return result
+}
+
+// Problems returns the warning of a graceful parsing along with the problems
+// found by Validate, sorted by line. Validate owns the checks of the values,
+// the parse warnings about a value it reports being dropped so that each
+// invalid value is reported once.
+func (e *Editorconfig) Problems(warning error) []*ParseError {
+	type property struct {
+		filename string
+		line     int
+		key      string
+	}
+
+	validated := ParseErrors(e.Validate())
+	invalid := make(map[property]bool)
+
+	for _, pe := range validated {
+		if errors.Is(pe, ErrInvalidValue) {
+			invalid[property{pe.Filename, pe.Line, pe.Key}] = true
+		}
+	}
+
+	var problems []*ParseError
+
+	for _, pe := range ParseErrors(warning) {
+		if pe.Key == "" || !invalid[property{pe.Filename, pe.Line, pe.Key}] {
+			problems = append(problems, pe)
+		}
+	}
+
+	problems = append(problems, validated...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return problems
+}
+
+// validate checks the properties of a section, in the order of the file.
+func (d *Definition) validate() []*ParseError {
+	var problems []*ParseError
+
+	for _, key := range d.keys() {
+		value := d.Raw[key]
+
+		pe := &ParseError{
+			Filename: d.filename,
+			Line:     d.lines[key],
+			Column:   d.keyColumns[key],
+			Selector: d.Selector,
+			Key:      key,
+			Value:    value,
+			Severity: SeverityWarning,
+		}
+
+		p, ok := LookupProperty(key)
+
+		switch {
+		case !ok:
+			pe.Err = fmt.Errorf("%w %s", ErrUnknownProperty, key)
+
+			if suggestion := suggestProperty(key); suggestion != "" {
+				pe.Err = fmt.Errorf("%w %s, did you mean %s?", ErrUnknownProperty, key, suggestion)
+			}
+		case key == "root":
+			pe.Err = ErrRootInSection
+		default:
+			if err := p.Check(value); err != nil {
+				pe.Column = d.columns[key]
+				pe.Err = err
+			}
+		}
+
+		if pe.Err != nil {
+			problems = append(problems, pe)
+		}
+	}
+
+	return problems
+}
+
+// suggestProperty returns the registered property the closest to the name,
+// empty if none is close enough.
+func suggestProperty(name string) string {
+	best := ""
+	bestDistance := len(name)/3 + 1
+
+	for _, p := range Properties() {
+		if strings.HasSuffix(p.Name, "*") {
+			continue
+		}
+
+		if distance := levenshtein(name, p.Name); distance < bestDistance {
+			best = p.Name
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between two strings, a swap of two
+// adjacent characters counting as one edit.
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// the last three rows of the matrix.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+
+		prev2, prev, curr = prev, curr, prev2
+	}
+
+	return prev[len(rb)]
+}
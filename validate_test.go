@@ -0,0 +1,102 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"root = true",
+		"[*]",
+		"indent_szie = 4",
+		"indent_style = tabs",
+		"end_of_line = LF",
+		"tab_width = -2",
+		"charset = utf-8",
+		"charset = latin1",
+		"root = true",
+		"ij_continuation_indent_size = 8",
+		"[*.md]",
+		"indent_size = four",
+		"max_line_length = off",
+	}, "\n")
+
+	ec, err := Parse(strings.NewReader(data))
+	assert.Nil(t, err)
+
+	problems := ParseErrors(ec.Validate())
+	assert.Equal(t, 6, len(problems))
+
+	expected := []struct {
+		line   int
+		column int
+		err    error
+	}{
+		{3, 1, ErrUnknownProperty},
+		{4, 16, ErrInvalidValue},
+		{6, 13, ErrInvalidValue},
+		{8, 1, ErrDuplicateKey},
+		{9, 1, ErrRootInSection},
+		{12, 15, ErrInvalidValue},
+	}
+
+	for i, e := range expected {
+		assert.Equal(t, e.line, problems[i].Line)
+		assert.Equal(t, e.column, problems[i].Column)
+		assert.Equal(t, true, errors.Is(problems[i], e.err))
+		assert.Equal(t, SeverityWarning, problems[i].Severity)
+	}
+
+	assert.Equal(t, "unknown property indent_szie, did you mean indent_size?", problems[0].Err.Error())
+}
+
+func TestProblems(t *testing.T) {
+	t.Parallel()
+
+	data := strings.Join([]string{
+		"root = true",
+		"[*]",
+		"max_line_length = x",
+		"trim_trailing_whitespace = maybe",
+		"not a property",
+		"indent_style = tabs",
+	}, "\n")
+
+	ec, warning, err := ParseGraceful(strings.NewReader(data))
+	assert.Nil(t, err)
+
+	problems := ec.Problems(warning)
+	assert.Equal(t, 4, len(problems))
+
+	expected := []struct {
+		line int
+		err  error
+	}{
+		{3, ErrInvalidValue},
+		{4, ErrInvalidValue},
+		{5, ErrInvalidLine},
+		{6, ErrInvalidValue},
+	}
+
+	for i, e := range expected {
+		assert.Equal(t, e.line, problems[i].Line)
+		assert.Equal(t, true, errors.Is(problems[i], e.err))
+	}
+
+	assert.Equal(t, "trim_trailing_whitespace=maybe, expected true or false: invalid value", problems[1].Err.Error())
+}
+
+func TestLevenshtein(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, levenshtein("charset", "charset"))
+	assert.Equal(t, 1, levenshtein("indent_szie", "indent_size"))
+	assert.Equal(t, 1, levenshtein("charst", "charset"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
@@ -0,0 +1,218 @@
+package editorconfig
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WalkFunc is the type of the function called by WalkDefinitions for each
+// file or directory, the definition being nil for the directories.
+//
+// As with fs.WalkDirFunc, returning fs.SkipDir skips the current directory,
+// or the remaining files of the current directory, and fs.SkipAll skips all
+// the remaining files.
+type WalkFunc func(path string, d fs.DirEntry, def *Definition, err error) error
+
+// walkLevel is a directory containing a configuration file.
+type walkLevel struct {
+	dir string
+	ec  *Editorconfig
+}
+
+// WalkDefinitions walks the file tree rooted at root, calling fn for each
+// file with its definition, see Config.WalkDefinitions.
+func WalkDefinitions(root string, fn WalkFunc) error {
+	return new(Config).WalkDefinitions(root, fn)
+}
+
+// WalkDefinitions walks the file tree rooted at root, calling fn for each
+// file with its definition. The root is read from the FS when set.
+//
+// Each configuration file is parsed once, and reused for all the files below
+// its directory. The errors and warnings found while parsing the
+// configuration file of a directory are given to fn along with the
+// directory; its files are then walked unless fn returns an error. The ones
+// of the configuration files above the root are given first, with a nil
+// fs.DirEntry.
+func (config *Config) WalkDefinitions(root string, fn WalkFunc) error {
+	absRoot, parse, err := config.resolve(root)
+	if err != nil {
+		return err
+	}
+
+	definition, err := config.newDefinition()
+	if err != nil {
+		return err
+	}
+
+	// Match the selectors using the cached regular expressions.
+	walkConfig := *config
+	if walkConfig.Parser == nil {
+		walkConfig.Parser = NewCachedParser()
+	}
+
+	w := &walker{
+		config:  &walkConfig,
+		version: definition.version,
+		parse:   parse,
+		absRoot: absRoot,
+		root:    root,
+		fn:      fn,
+	}
+
+	// The configuration files above the root.
+	parents, warning, err := w.levels(filepath.Dir(absRoot), false)
+	if err != nil || warning != nil {
+		err = fn(root, nil, nil, errors.Join(warning, err))
+		if err != nil {
+			return ignoreSkip(err)
+		}
+	}
+
+	w.parents = parents
+
+	if config.FS != nil {
+		err = fs.WalkDir(config.FS, root, w.walk)
+	} else {
+		err = filepath.WalkDir(root, w.walk)
+	}
+
+	return ignoreSkip(err)
+}
+
+type walker struct {
+	config  *Config
+	version string
+	parse   func(string) (*Editorconfig, error, error)
+	absRoot string
+	root    string
+	fn      WalkFunc
+
+	// parents are the configuration files above the root, the closest
+	// first, and stack the ones of the directories being walked.
+	parents []walkLevel
+	stack   []walkLevel
+}
+
+func (w *walker) walk(name string, d fs.DirEntry, err error) error {
+	absName, relErr := w.abs(name)
+	if relErr != nil {
+		return relErr
+	}
+
+	// Leave the directories not containing the current file.
+	for len(w.stack) > 0 && !within(absName, w.stack[len(w.stack)-1].dir) {
+		w.stack = w.stack[:len(w.stack)-1]
+	}
+
+	if err != nil {
+		return w.fn(name, d, nil, err)
+	}
+
+	if d.IsDir() {
+		levels, warning, err := w.levels(absName, true)
+		w.stack = append(w.stack, levels...)
+
+		return w.fn(name, d, nil, errors.Join(warning, err))
+	}
+
+	def, err := w.resolve(absName)
+
+	return w.fn(name, d, def, err)
+}
+
+// levels parses the configuration file of the directory, or of the
+// directory and all its parents up to the root one.
+func (w *walker) levels(dir string, single bool) ([]walkLevel, error, error) {
+	var (
+		levels  []walkLevel
+		warning error
+	)
+
+	ecFile := w.config.ConfigName()
+
+	for {
+		ec, warn, err := w.parse(filepath.Join(dir, ecFile))
+		if warn != nil {
+			warning = errors.Join(warning, warn)
+		}
+
+		switch {
+		case err == nil:
+			levels = append(levels, walkLevel{dir: dir, ec: ec})
+		case !errors.Is(err, os.ErrNotExist):
+			return levels, warning, fmt.Errorf("cannot parse the ini file %q: %w", ecFile, err)
+		}
+
+		if single || (err == nil && ec.Root) || dir == filepath.Dir(dir) {
+			return levels, warning, nil
+		}
+
+		dir = filepath.Dir(dir)
+	}
+}
+
+// resolve merges the definitions of the configuration files of the stack,
+// then of the parents, up to the root one.
+func (w *walker) resolve(absName string) (*Definition, error) {
+	definition := &Definition{
+		Raw:     make(map[string]string),
+		version: w.version,
+	}
+
+	for i := len(w.stack) - 1; i >= 0; i-- {
+		level := w.stack[i]
+
+		if err := w.config.merge(definition, level.ec, level.dir, absName); err != nil {
+			return nil, err
+		}
+
+		if level.ec.Root {
+			return definition, nil
+		}
+	}
+
+	for _, level := range w.parents {
+		if err := w.config.merge(definition, level.ec, level.dir, absName); err != nil {
+			return nil, err
+		}
+	}
+
+	return definition, nil
+}
+
+// abs returns the absolute version of a walked path.
+func (w *walker) abs(name string) (string, error) {
+	if w.config.FS != nil {
+		return path.Join("/", name), nil
+	}
+
+	rel, err := filepath.Rel(w.root, name)
+	if err != nil {
+		return "", fmt.Errorf("cannot get relative path of %q: %w", name, err)
+	}
+
+	return filepath.Join(w.absRoot, rel), nil
+}
+
+// within tells whether the name is the directory or one of its descendants.
+func within(name string, dir string) bool {
+	if !strings.HasPrefix(name, dir) {
+		return false
+	}
+
+	return len(name) == len(dir) || strings.HasSuffix(dir, string(filepath.Separator)) || name[len(dir)] == filepath.Separator
+}
+
+func ignoreSkip(err error) error {
+	if errors.Is(err, fs.SkipDir) || errors.Is(err, fs.SkipAll) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,159 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func TestWalkDefinitions(t *testing.T) {
+	t.Parallel()
+
+	count := 0
+
+	err := WalkDefinitions("testdata", func(path string, d fs.DirEntry, def *Definition, err error) error {
+		assert.Nil(t, err)
+
+		if d.IsDir() {
+			assert.Equal(t, (*Definition)(nil), def)
+
+			if d.Name() == "core-test" {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		count++
+
+		expected, err := GetDefinitionForFilename(path)
+		assert.Nil(t, err)
+		assert.Equal(t, true, equalDefinitions(expected, def))
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 6, count)
+}
+
+func TestWalkDefinitionsFS(t *testing.T) {
+	t.Parallel()
+
+	config := &Config{FS: mapFS(testFiles)}
+	definitions := make(map[string]string)
+
+	err := config.WalkDefinitions(".", func(path string, d fs.DirEntry, def *Definition, err error) error {
+		assert.Nil(t, err)
+
+		if def != nil {
+			definitions[path] = def.IndentStyle
+		}
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		".editorconfig":     "",
+		"src/.editorconfig": "",
+		"src/main.go":       IndentStyleTab,
+	}, definitions)
+}
+
+func TestWalkDefinitionsSkip(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+
+	err := WalkDefinitions("testdata/root/src", func(path string, d fs.DirEntry, _ *Definition, err error) error {
+		assert.Nil(t, err)
+
+		paths = append(paths, path)
+
+		if d.Name() == "a.ini" {
+			return fs.SkipDir
+		}
+
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"testdata/root/src", "testdata/root/src/.editorconfig", "testdata/root/src/a.ini"}, paths)
+}
+
+// benchmarkTree creates a tree of 10 directories of 100 files.
+func benchmarkTree(b *testing.B) string {
+	b.Helper()
+
+	dir := b.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, ".editorconfig"), []byte(`root = true
+[*]
+indent_style = space
+[*.{go,mod}]
+indent_style = tab
+[{Makefile,*.mk}]
+indent_style = tab
+[*.{md,txt}]
+trim_trailing_whitespace = false
+`), 0o600)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for i := range 10 {
+		sub := filepath.Join(dir, fmt.Sprintf("dir%d", i))
+		if err := os.Mkdir(sub, 0o700); err != nil {
+			b.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(sub, ".editorconfig"), []byte("[*.go]\nindent_size = 4\n"), 0o600); err != nil {
+			b.Fatal(err)
+		}
+
+		for j := range 100 {
+			if err := os.WriteFile(filepath.Join(sub, fmt.Sprintf("file%d.go", j)), nil, 0o600); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	return dir
+}
+
+func BenchmarkWalkDefinitions(b *testing.B) {
+	dir := benchmarkTree(b)
+
+	for b.Loop() {
+		err := WalkDefinitions(dir, func(string, fs.DirEntry, *Definition, error) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoad(b *testing.B) {
+	dir := benchmarkTree(b)
+
+	for b.Loop() {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+
+			_, err = GetDefinitionForFilename(path)
+
+			return err
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package editorconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event tells that the definition of a watched file has changed.
+type Event struct {
+	Filename string
+	Old      *Definition
+	New      *Definition
+}
+
+// Watcher polls the configuration files applying to a set of files, up to
+// the one with root = true, and reports the changes of their definition.
+//
+// When the Parser of the config has an Invalidate(filename string) method,
+// like the CachedParser, it is called for each modified configuration file.
+//
+// The channels are not buffered, both must be received from: the polling
+// waits for its events and errors to be received, or for Close.
+type Watcher struct {
+	// Events are the changes of definition of the watched files.
+	Events chan Event
+	// Errors are the errors encountered while reloading a definition, the
+	// changes being looked at again by the next poll.
+	Errors chan error
+
+	config   *Config
+	interval time.Duration
+
+	mu    sync.Mutex
+	files map[string]*watchedFile
+	// states are the last known states of the configuration files.
+	states map[string]fileState
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// watchedFile is a file and the configuration files applying to it.
+type watchedFile struct {
+	def     *Definition
+	configs []string
+	// states are the states of the configuration files before loading.
+	states map[string]fileState
+}
+
+// fileState is what is compared between two polls.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher starts polling the configuration files at the given interval.
+func NewWatcher(config *Config, interval time.Duration) *Watcher {
+	w := &Watcher{
+		Events:   make(chan Event),
+		Errors:   make(chan error),
+		config:   config,
+		interval: interval,
+		files:    make(map[string]*watchedFile),
+		states:   make(map[string]fileState),
+		done:     make(chan struct{}),
+	}
+
+	w.wg.Add(1)
+
+	go w.run()
+
+	return w
+}
+
+// Add starts watching the definition of the given file.
+func (w *Watcher) Add(filename string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	file, err := w.load(filename)
+	if err != nil {
+		return err
+	}
+
+	w.files[filename] = file
+	w.track(file)
+
+	return nil
+}
+
+// Remove stops watching the definition of the given file.
+func (w *Watcher) Remove(filename string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.files, filename)
+	w.untrack()
+}
+
+// Close stops the polling and closes the channels.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.wg.Wait()
+		close(w.Events)
+		close(w.Errors)
+	})
+
+	return nil
+}
+
+func (w *Watcher) run() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+// poll looks for modified configuration files and reloads the definition of
+// the files they apply to.
+func (w *Watcher) poll() {
+	w.mu.Lock()
+
+	// changed are the new states of the modified configuration files, kept
+	// unless a file they apply to fails to reload.
+	changed := make(map[string]fileState)
+	failed := make(map[string]bool)
+
+	for name, state := range w.states {
+		current := w.stat(name)
+		if current != state {
+			changed[name] = current
+
+			if parser, ok := w.config.Parser.(interface{ Invalidate(filename string) }); ok {
+				parser.Invalidate(w.parserFilename(name))
+			}
+		}
+	}
+
+	var (
+		events []Event
+		errs   []error
+	)
+
+	for filename, file := range w.files {
+		if !affected(file.configs, changed) {
+			continue
+		}
+
+		reloaded, err := w.load(filename)
+		if err != nil {
+			errs = append(errs, err)
+
+			// the next poll retries.
+			for _, name := range file.configs {
+				failed[name] = true
+			}
+
+			continue
+		}
+
+		w.files[filename] = reloaded
+		w.track(reloaded)
+
+		if !equalDefinitions(file.def, reloaded.def) {
+			events = append(events, Event{
+				Filename: filename,
+				Old:      file.def,
+				New:      reloaded.def,
+			})
+		}
+	}
+
+	for name, state := range changed {
+		if _, ok := w.states[name]; ok && !failed[name] {
+			w.states[name] = state
+		}
+	}
+
+	w.untrack()
+	w.mu.Unlock()
+
+	for _, event := range events {
+		select {
+		case w.Events <- event:
+		case <-w.done:
+			return
+		}
+	}
+
+	for _, err := range errs {
+		select {
+		case w.Errors <- err:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// load resolves the definition of the file and the configuration files
+// applying to it. The configuration files are looked at before loading, so
+// that a change made meanwhile is seen by the next poll. The warnings do not
+// prevent the loading.
+func (w *Watcher) load(filename string) (*watchedFile, error) {
+	absFilename, _, err := w.config.resolve(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ecFile := w.config.ConfigName()
+
+	file := &watchedFile{
+		states: make(map[string]fileState),
+	}
+
+	for dir := absFilename; dir != filepath.Dir(dir); {
+		dir = filepath.Dir(dir)
+
+		name := filepath.Join(dir, ecFile)
+		file.states[name] = w.stat(name)
+	}
+
+	file.def, file.configs, _, err = w.config.loadGraceful(filename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the definition of %q: %w", filename, err)
+	}
+
+	return file, nil
+}
+
+// track records the state of the new configuration files of the file, as
+// it was before loading.
+func (w *Watcher) track(file *watchedFile) {
+	for _, name := range file.configs {
+		if _, ok := w.states[name]; !ok {
+			w.states[name] = file.states[name]
+		}
+	}
+}
+
+// untrack forgets the configuration files not applying to any file.
+func (w *Watcher) untrack() {
+	used := make(map[string]bool)
+
+	for _, file := range w.files {
+		for _, name := range file.configs {
+			used[name] = true
+		}
+	}
+
+	for name := range w.states {
+		if !used[name] {
+			delete(w.states, name)
+		}
+	}
+}
+
+func (w *Watcher) stat(name string) fileState {
+	var (
+		info fs.FileInfo
+		err  error
+	)
+
+	if w.config.FS == nil {
+		info, err = os.Stat(name)
+	} else {
+		info, err = fs.Stat(w.config.FS, w.parserFilename(name))
+	}
+
+	if err != nil {
+		return fileState{}
+	}
+
+	return fileState{
+		exists:  true,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
+
+// parserFilename returns the filename as given to the parser.
+func (w *Watcher) parserFilename(name string) string {
+	if w.config.FS == nil {
+		return name
+	}
+
+	return strings.TrimPrefix(filepath.ToSlash(name), "/")
+}
+
+func affected(configs []string, changed map[string]fileState) bool {
+	for _, name := range configs {
+		if _, ok := changed[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// equalDefinitions compares the properties of two definitions.
+func equalDefinitions(a *Definition, b *Definition) bool {
+	return a.Charset == b.Charset &&
+		a.IndentStyle == b.IndentStyle &&
+		a.IndentSize == b.IndentSize &&
+		a.TabWidth == b.TabWidth &&
+		a.EndOfLine == b.EndOfLine &&
+		equalBools(a.TrimTrailingWhitespace, b.TrimTrailingWhitespace) &&
+		equalBools(a.InsertFinalNewline, b.InsertFinalNewline) &&
+		maps.Equal(a.Raw, b.Raw)
+}
+
+func equalBools(a *bool, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,218 @@
+package editorconfig //nolint:testpackage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/editorconfig/editorconfig-core-go/v2/internal/assert"
+)
+
+func writeFile(t *testing.T, filename string, content string, modTime time.Time) {
+	t.Helper()
+
+	err := os.WriteFile(filename, []byte(content), 0o600)
+	assert.Nil(t, err)
+
+	// the modification time might not change on fast writes.
+	err = os.Chtimes(filename, modTime, modTime)
+	assert.Nil(t, err)
+}
+
+func nextEvent(t *testing.T, w *Watcher) Event {
+	t.Helper()
+
+	select {
+	case event := <-w.Events:
+		return event
+	case err := <-w.Errors:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for an event")
+	}
+
+	return Event{}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	filename := filepath.Join(src, "main.go")
+	start := time.Now().Add(-time.Hour)
+
+	assert.Nil(t, os.Mkdir(src, 0o700))
+	writeFile(t, filepath.Join(dir, ".editorconfig"), "root = true\n[*.go]\nindent_style = space\n", start)
+	writeFile(t, filename, "package main\n", start)
+
+	w := NewWatcher(&Config{Parser: NewCachedParser()}, 10*time.Millisecond)
+
+	defer w.Close()
+
+	assert.Nil(t, w.Add(filename))
+
+	// modify the root file
+	writeFile(t, filepath.Join(dir, ".editorconfig"), "root = true\n[*.go]\nindent_style = tab\n", start.Add(time.Minute))
+
+	event := nextEvent(t, w)
+	assert.Equal(t, filename, event.Filename)
+	assert.Equal(t, IndentStyleSpaces, event.Old.IndentStyle)
+	assert.Equal(t, IndentStyleTab, event.New.IndentStyle)
+
+	// create a file closer to the watched file
+	writeFile(t, filepath.Join(src, ".editorconfig"), "[*.go]\nindent_size = 2\n", start)
+
+	event = nextEvent(t, w)
+	assert.Equal(t, "", event.Old.IndentSize)
+	assert.Equal(t, "2", event.New.IndentSize)
+	assert.Equal(t, IndentStyleTab, event.New.IndentStyle)
+
+	// the parent of the root file is not watched.
+	writeFile(t, filepath.Join(src, ".editorconfig"), "root = true\n[*.go]\nindent_size = 2\n", start.Add(time.Minute))
+
+	event = nextEvent(t, w)
+	assert.Equal(t, IndentStyleTab, event.Old.IndentStyle)
+	assert.Equal(t, "", event.New.IndentStyle)
+
+	w.mu.Lock()
+	assert.Equal(t, []string{filepath.Join(src, ".editorconfig")}, w.files[filename].configs)
+	w.mu.Unlock()
+}
+
+// changingParser runs change once, after the first parsing.
+type changingParser struct {
+	SimpleParser
+
+	once   sync.Once
+	change func()
+}
+
+func (parser *changingParser) ParseIni(filename string) (*Editorconfig, error) {
+	defer parser.once.Do(parser.change)
+
+	return parser.SimpleParser.ParseIni(filename)
+}
+
+func (parser *changingParser) ParseIniGraceful(filename string) (*Editorconfig, error, error) {
+	defer parser.once.Do(parser.change)
+
+	return parser.SimpleParser.ParseIniGraceful(filename)
+}
+
+func TestWatcherChangeWhileAdding(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "main.go")
+	ecFile := filepath.Join(dir, ".editorconfig")
+	start := time.Now().Add(-time.Hour)
+
+	writeFile(t, ecFile, "root = true\n[*.go]\nindent_style = space\n", start)
+	writeFile(t, filename, "package main\n", start)
+
+	parser := &changingParser{
+		change: func() {
+			writeFile(t, ecFile, "root = true\n[*.go]\nindent_style = tab\n", start.Add(time.Minute))
+		},
+	}
+
+	w := NewWatcher(&Config{Parser: parser}, 10*time.Millisecond)
+
+	defer w.Close()
+
+	assert.Nil(t, w.Add(filename))
+
+	event := nextEvent(t, w)
+	assert.Equal(t, IndentStyleSpaces, event.Old.IndentStyle)
+	assert.Equal(t, IndentStyleTab, event.New.IndentStyle)
+}
+
+func TestWatcherWarnings(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "main.go")
+	start := time.Now().Add(-time.Hour)
+
+	writeFile(t, filepath.Join(dir, ".editorconfig"), "root = true\n[*.go]\nindent_style = space\nnot a property\n", start)
+	writeFile(t, filename, "package main\n", start)
+
+	w := NewWatcher(&Config{Parser: NewCachedParser()}, 10*time.Millisecond)
+
+	defer w.Close()
+
+	assert.Nil(t, w.Add(filename))
+
+	w.mu.Lock()
+	assert.Equal(t, IndentStyleSpaces, w.files[filename].def.IndentStyle)
+	assert.Equal(t, []string{filepath.Join(dir, ".editorconfig")}, w.files[filename].configs)
+	w.mu.Unlock()
+}
+
+var errFailing = errors.New("failing")
+
+// failingParser fails while fail is set.
+type failingParser struct {
+	SimpleParser
+
+	fail atomic.Bool
+}
+
+func (parser *failingParser) ParseIniGraceful(filename string) (*Editorconfig, error, error) {
+	if parser.fail.Load() {
+		return nil, nil, errFailing
+	}
+
+	return parser.SimpleParser.ParseIniGraceful(filename)
+}
+
+func TestWatcherRetriesFailedReload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "main.go")
+	ecFile := filepath.Join(dir, ".editorconfig")
+	start := time.Now().Add(-time.Hour)
+
+	writeFile(t, ecFile, "root = true\n[*.go]\nindent_style = space\n", start)
+	writeFile(t, filename, "package main\n", start)
+
+	parser := &failingParser{}
+	w := NewWatcher(&Config{Parser: parser}, 10*time.Millisecond)
+
+	defer w.Close()
+
+	assert.Nil(t, w.Add(filename))
+
+	parser.fail.Store(true)
+	writeFile(t, ecFile, "root = true\n[*.go]\nindent_style = tab\n", start.Add(time.Minute))
+
+	select {
+	case err := <-w.Errors:
+		assert.Equal(t, true, errors.Is(err, errFailing))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for an error")
+	}
+
+	parser.fail.Store(false)
+
+	// the change is seen again, the errors of the polls meanwhile being
+	// ignored.
+	for {
+		select {
+		case event := <-w.Events:
+			assert.Equal(t, IndentStyleSpaces, event.Old.IndentStyle)
+			assert.Equal(t, IndentStyleTab, event.New.IndentStyle)
+
+			return
+		case <-w.Errors:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for an event")
+		}
+	}
+}